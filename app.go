@@ -0,0 +1,245 @@
+package boa
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AppInfo describes the application a schema is for. It is read from the
+// "app" key at the top of a schema:
+//
+//	{"app": {"Name": "tool", "Version": "1.4.0", "Authors": ["Ann <ann@example.com>"]},
+//	 "commands": [...]}
+//
+// Schemas without that key may still give Name, as Alias, and Version,
+// Commit and BuildDate in the BOA-APP-DATA record.
+type AppInfo struct {
+	Name        string
+	Version     string
+	Commit      string
+	BuildDate   string
+	Description string
+	Authors     []string
+	Homepage    string
+	License     string
+	Examples    []Example // shown after the items in usage and man output
+	Epilog      string    // the last words of usage and man output
+}
+
+// Example is a command line with what it does.
+type Example struct {
+	Line        string // as typed, starting with the application name
+	Description string
+}
+
+func (a AppInfo) clone() AppInfo {
+	a.Authors = slices.Clone(a.Authors)
+	a.Examples = slices.Clone(a.Examples)
+	return a
+}
+
+// WithApp sets the application info, in place of what the schema gives.
+func WithApp(info AppInfo) Option {
+	return func(C *CLI) {
+		info = info.clone()
+		C.conf.app = &info
+	}
+}
+
+// AppFromJSON reads the AppInfo of a schema, from its "app" key or else
+// from its BOA-APP-DATA record.
+func AppFromJSON(jsonBytes []byte) (AppInfo, error) {
+	var jslice sliceWrap
+	if err := json.Unmarshal(jsonBytes, &jslice); err != nil {
+		return AppInfo{}, err
+	}
+	var info AppInfo
+	for _, it := range jslice.Commands {
+		if it.Name == AppDataName() {
+			info = appFromItem(it)
+		}
+	}
+	if jslice.App != nil {
+		name := info.Name
+		info = *jslice.App
+		info.Name = first(info.Name, name)
+	}
+	return info, nil
+}
+
+// appFromItem reads the fields an AppInfo has in a BOA-APP-DATA record.
+func appFromItem(it CmdLineItem) AppInfo {
+	return AppInfo{
+		Name:      it.Alias, //app name is in alias field in that special item
+		Version:   it.Version,
+		Commit:    it.Commit,
+		BuildDate: it.BuildDate,
+	}
+}
+
+// App returns the application info.
+func (C *CLI) App() AppInfo {
+	return C.app.clone()
+}
+
+// usageHead is the description that follows the "Usage of" line, empty
+// when there is none.
+func (C *CLI) usageHead() string {
+	if C.app.Description == "" {
+		return ""
+	}
+	return strings.TrimRight(C.app.Description, "\n") + "\n\n"
+}
+
+// usageTail is what follows the items in usage: the examples and the
+// epilog.
+func (C *CLI) usageTail() string {
+	var b strings.Builder
+	if len(C.app.Examples) > 0 {
+		heading := "Examples:"
+		if th := C.conf.styles(); th != nil {
+			heading = th.Heading.paint(heading)
+		}
+		b.WriteString("\n" + heading + "\n")
+		b.WriteString(formatExamples(C.app.Examples))
+	}
+	if C.app.Epilog != "" {
+		b.WriteString("\n" + strings.TrimRight(C.app.Epilog, "\n") + "\n")
+	}
+	return b.String()
+}
+
+// withExamples adds the examples of an item to its help as formatHelp
+// gives it.
+func withExamples(help string, examples []Example, th *Theme) string {
+	if len(examples) == 0 {
+		return help
+	}
+	heading := "Examples:"
+	if th != nil {
+		heading = th.Heading.paint(heading)
+	}
+	return strings.TrimRight(help, "\n") + "\n" + heading + "\n" + formatExamples(examples)
+}
+
+func formatExamples(examples []Example) string {
+	var b strings.Builder
+	for _, e := range examples {
+		b.WriteString("  " + e.Line + "\n")
+		if e.Description != "" {
+			b.WriteString("      " + e.Description + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Man returns a manual page for the application in roff, for man(1). The
+// section is 1 and the date the BuildDate, or today when there is none.
+func (C *CLI) Man() string {
+	name := first(C.app.Name, C.Application, "app")
+	date := first(C.app.BuildDate, time.Now().Format("2006-01-02"))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %s 1 %s %s\n", roffQuote(strings.ToUpper(name)), roffQuote(date), roffQuote(strings.TrimSpace(name+" "+C.app.Version)))
+	b.WriteString(".SH NAME\n")
+	if short := firstLine(C.app.Description); short != "" {
+		b.WriteString(roffEscape(name) + " \\- " + roffEscape(short) + "\n")
+	} else {
+		b.WriteString(roffEscape(name) + "\n")
+	}
+	b.WriteString(".SH SYNOPSIS\n.B " + roffEscape(name) + "\n[\\fIOPTIONS\\fR]\n")
+	if C.app.Description != "" {
+		b.WriteString(".SH DESCRIPTION\n" + roffText(C.app.Description))
+	}
+
+	if names := C.helpOrder(); len(names) > 0 {
+		b.WriteString(".SH OPTIONS\n")
+		for _, name := range names {
+			if C.index == nil {
+				break
+			}
+			it, ok := C.index.cmds[name]
+			if !ok {
+				continue
+			}
+			head := strings.Join(append([]string{it.Name}, it.AllAliases()...), ", ")
+			if ph := it.Placeholder(); ph != "" {
+				head += " " + ph
+			}
+			b.WriteString(".TP\n.B " + roffEscape(head) + "\n")
+			short, long := C.conf.helpOf(it)
+			b.WriteString(roffText(helpText(CmdLineItem{Name: it.Name, ShortHelp: short})))
+			if long != "" {
+				b.WriteString(roffText(long))
+			}
+			if len(it.Examples) > 0 {
+				b.WriteString(".RS\n" + roffExamples(it.Examples) + ".RE\n")
+			}
+		}
+	}
+
+	if len(C.app.Examples) > 0 {
+		b.WriteString(".SH EXAMPLES\n")
+		b.WriteString(roffExamples(C.app.Examples))
+	}
+	if C.app.Epilog != "" {
+		b.WriteString(".PP\n" + roffText(C.app.Epilog))
+	}
+	if len(C.app.Authors) > 0 {
+		b.WriteString(".SH AUTHORS\n" + roffText(strings.Join(C.app.Authors, "\n.br\n")))
+	}
+	if C.app.License != "" {
+		b.WriteString(".SH LICENSE\n" + roffText(C.app.License))
+	}
+	if C.app.Homepage != "" {
+		b.WriteString(".SH SEE ALSO\n" + roffText(C.app.Homepage))
+	}
+	return b.String()
+}
+
+func roffExamples(examples []Example) string {
+	var b strings.Builder
+	for _, e := range examples {
+		b.WriteString(".PP\n.nf\n.RS\n" + roffEscape(e.Line) + "\n.RE\n.fi\n")
+		if e.Description != "" {
+			b.WriteString(roffText(e.Description))
+		}
+	}
+	return b.String()
+}
+
+// roffText escapes s for roff and ends it with a newline. Lines that are
+// requests, such as .br, are kept as they are.
+func roffText(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		if l == ".br" {
+			continue
+		}
+		lines[i] = roffEscape(strings.TrimSpace(l))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// roffEscape keeps backslashes, dashes and leading dots and quotes from
+// being read as roff.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(roffEscape(s), `"`, `\(dq`) + `"`
+}
+
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return s
+}
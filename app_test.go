@@ -0,0 +1,63 @@
+package boa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppFromJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		schema string
+		want   AppInfo
+	}{
+		{"app key",
+			`{"app": {"Name": "tool", "Version": "1.4.0", "Authors": ["Ann <ann@example.com>"],
+			  "Examples": [{"Line": "tool --verbose", "Description": "say more"}]},
+			  "commands": [{"Id": 1, "Name": "--verbose", "IsFlag": true}]}`,
+			AppInfo{Name: "tool", Version: "1.4.0", Authors: []string{"Ann <ann@example.com>"},
+				Examples: []Example{{"tool --verbose", "say more"}}}},
+		{"app data record",
+			`{"commands": [{"Id": 0, "Name": "BOA-APP-DATA", "Alias": "old", "Version": "0.9", "Commit": "abc", "BuildDate": "2024-01-02"},
+			  {"Id": 1, "Name": "--verbose", "IsFlag": true}]}`,
+			AppInfo{Name: "old", Version: "0.9", Commit: "abc", BuildDate: "2024-01-02"}},
+		{"app key wins over the record",
+			`{"app": {"Version": "2.0"},
+			  "commands": [{"Id": 0, "Name": "BOA-APP-DATA", "Alias": "old", "Version": "0.9"}]}`,
+			AppInfo{Name: "old", Version: "2.0"}},
+		{"neither", `{"commands": [{"Id": 1, "Name": "--verbose", "IsFlag": true}]}`, AppInfo{}},
+	} {
+		got, err := AppFromJSON([]byte(tc.schema))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+
+		p, err := NewParserFromJSON([]byte(tc.schema))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if _, ok := p.Items()[AppDataName()]; ok {
+			t.Errorf("%s: the app data record is one of the items", tc.name)
+		}
+		cli := p.Parse(nil)
+		if cli.Application != tc.want.Name || !reflect.DeepEqual(cli.App(), p.App()) {
+			t.Errorf("%s: Application = %q, App() = %+v", tc.name, cli.Application, cli.App())
+		}
+	}
+}
+
+func TestWithApp(t *testing.T) {
+	schema := `{"commands": [{"Id": 0, "Name": "BOA-APP-DATA", "Alias": "old", "Version": "0.9"}]}`
+	authors := []string{"Ann"}
+	p, err := NewParserFromJSON([]byte(schema), WithApp(AppInfo{Name: "new", Authors: authors}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authors[0] = "Bob"
+	if app := p.App(); app.Name != "new" || app.Version != "" || app.Authors[0] != "Ann" {
+		t.Errorf("App() = %+v, want the info given to WithApp", app)
+	}
+}
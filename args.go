@@ -0,0 +1,332 @@
+package boa
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Args renders the parsed items back into a command line that parses to
+// the same values. Items come in the order they are defined and by their
+// long names; slices and maps are ended with '--', a value that came from
+// DefaultValue is left out in favour of '--', and unknown and passthrough
+// arguments follow the items. The program name is not included.
+//
+// The arguments are read back the same by a Parser with the same items
+// and options. A value no argument can carry, such as a sub command name
+// given as the value of its own command, is not round-tripped.
+func (C *CLI) Args() []string {
+	names := make([]string, 0, len(C.Items))
+	for name := range C.Items {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := C.Items[names[i]], C.Items[names[j]]
+		// an optional item given no value only reads the same when last
+		if (a.Value == nil) != (b.Value == nil) {
+			return b.Value == nil
+		}
+		if a.Id != b.Id {
+			return a.Id < b.Id
+		}
+		return a.Name < b.Name
+	})
+
+	var args []string
+	if C.experimental && !C.conf.experimental && C.conf.experimentalFlag != "" {
+		args = append(args, C.conf.experimentalFlag)
+	}
+	for _, name := range names {
+		args = append(args, C.itemArgs(C.Items[name])...)
+	}
+	args = append(args, C.unknown...)
+	if len(C.passed) > 0 {
+		args = append(args, "--")
+		args = append(args, C.passed...)
+	}
+	return args
+}
+
+// CommandLine is Args quoted for a POSIX shell, as one string that
+// SplitArgs and ParseString read back into the same arguments.
+func (C *CLI) CommandLine() string {
+	return JoinArgs(C.Args())
+}
+
+func (C *CLI) itemArgs(it CmdLineItem) []string {
+	if it.ParamCount == 0 || it.ParamType == TypeBool {
+		return []string{it.Name}
+	}
+	tuple := it.ParamCount > 1 && !it.ParamType.IsSlice()
+
+	args := []string{it.Name}
+	if !tuple {
+		args = append(args, it.ChNames...)
+	}
+	if it.Source == SourceDefault {
+		return append(args, "--")
+	}
+
+	var vals []string
+	switch v := it.Value.(type) {
+	case nil:
+		return args
+	case []interface{}:
+		for pos, e := range v {
+			vals = append(vals, formatValue(it.paramType(pos), e))
+		}
+	default:
+		vals = formatValues(it, v)
+	}
+
+	long := len(args) == 1 && strings.HasPrefix(it.Name, "--") && C.conf.dialect != DialectPOSIX
+	if it.Separator != "" && (it.ParamType.IsSlice() || it.ParamType.IsMap()) {
+		vals = C.groupValues(it, vals, long)
+	}
+	if len(vals) > 0 && long && attached(vals[0]) {
+		args[0] += "=" + vals[0]
+		vals = vals[1:]
+	}
+	args = append(args, vals...)
+	if it.ParamType.IsSlice() || it.ParamType.IsMap() {
+		args = append(args, "--")
+	}
+	return args
+}
+
+// formatValues returns the values of an item as they are given on the
+// command line, one string for each.
+func formatValues(it CmdLineItem, v interface{}) []string {
+	var vals []string
+	switch {
+	case it.ParamType.IsMap():
+		switch m := v.(type) {
+		case map[string]string:
+			for k, e := range m {
+				vals = append(vals, k+"="+e)
+			}
+		case map[string]int:
+			for k, e := range m {
+				vals = append(vals, k+"="+strconv.Itoa(e))
+			}
+		}
+		sort.Strings(vals)
+	case it.ParamType.IsSlice():
+		switch s := v.(type) {
+		case []string:
+			vals = append(vals, s...)
+		case []int:
+			for _, e := range s {
+				vals = append(vals, strconv.Itoa(e))
+			}
+		case []float64:
+			for _, e := range s {
+				vals = append(vals, formatValue(TypeFloat, e))
+			}
+		case []time.Time:
+			elem := TypeTime
+			if it.ParamType == TypeDateSlice {
+				elem = TypeDate
+			}
+			for _, e := range s {
+				vals = append(vals, formatValue(elem, e))
+			}
+		case []time.Duration:
+			for _, e := range s {
+				vals = append(vals, e.String())
+			}
+		case []mail.Address:
+			for _, e := range s {
+				vals = append(vals, e.String())
+			}
+		case []url.URL:
+			for _, e := range s {
+				vals = append(vals, formatURL(e))
+			}
+		case []net.IP:
+			for _, e := range s {
+				vals = append(vals, e.String())
+			}
+		}
+	default:
+		return []string{formatValue(it.ParamType, v)}
+	}
+
+	return vals
+}
+
+// groupValues puts the values of an item with a Separator into
+// arguments. A value is given an argument of its own unless it would not
+// read back as itself there, such as '--' or what looks like a group of
+// flags, in which case it is joined to the argument before it. The count
+// of an item is of arguments, not of the values split out of them, so
+// when there are more arguments than it allows the last ones are joined.
+// long tells whether the first argument may be attached to the name.
+func (C *CLI) groupValues(it CmdLineItem, vals []string, long bool) []string {
+	render := func(g []string) string {
+		parts := make([]string, len(g))
+		for j, e := range g {
+			parts[j] = escapeSep(e, it.Separator, j == len(g)-1)
+		}
+		return strings.Join(parts, it.Separator)
+	}
+	ok := func(a string, pos int) bool {
+		if pos == 0 && long && attached(a) {
+			return true
+		}
+		if C.index != nil {
+			if _, isItem := C.index.lookup(a); isItem {
+				return false
+			}
+		}
+		return stableArg(a) && a != "--"
+	}
+
+	var groups [][]string
+	for _, e := range vals {
+		n := len(groups)
+		if n > 0 && (!ok(render([]string{e}), n) || !ok(render(groups[n-1]), n-1)) {
+			groups[n-1] = append(groups[n-1], e)
+			// joining may spoil the argument before, then join that too
+			for n > 1 && !ok(render(groups[n-1]), n-1) {
+				groups[n-2] = append(groups[n-2], groups[n-1]...)
+				groups = groups[:n-1]
+				n--
+			}
+			continue
+		}
+		groups = append(groups, []string{e})
+	}
+
+	max := len(groups)
+	switch {
+	case it.ParamCount > 0:
+		max = it.ParamCount
+	case it.ParamCount < 0 && it.ParamCount > ZeroOrMore:
+		max = -it.ParamCount
+	}
+	if max >= 1 && len(groups) > max {
+		for _, g := range groups[max:] {
+			groups[max-1] = append(groups[max-1], g...)
+		}
+		groups = groups[:max]
+	}
+
+	args := make([]string, len(groups))
+	for i, g := range groups {
+		args[i] = render(g)
+	}
+	return args
+}
+
+// attached reports whether itemArgs writes v, the first value of an item
+// with a long name, as --name=value. A value that looks like a flag only
+// stays whole that way. Anything else, '--' included, is an argument of
+// its own and has to read back as itself there.
+func attached(v string) bool {
+	return isFlagToken(v)
+}
+
+// stableArg reports whether a reads back as the single token it is.
+func stableArg(a string) bool {
+	return !isFlagToken(a) || utf8.ValidString(a) && utf8.RuneCountInString(a) == 2 || strings.HasPrefix(a, "--") && !strings.Contains(a, "=")
+}
+
+// escapeSep escapes v so that splitEscaped gives it back whole. Only what
+// splitEscaped would read differently is escaped: sep itself and a
+// backslash before sep or another backslash, or at the end of v when
+// more values follow.
+func escapeSep(v, sep string, last bool) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		switch {
+		case strings.HasPrefix(v[i:], sep):
+			b.WriteString(`\` + sep)
+			i += len(sep) - 1
+		case v[i] == '\\' && (strings.HasPrefix(v[i+1:], sep) || strings.HasPrefix(v[i+1:], `\`) || i == len(v)-1 && !last):
+			b.WriteString(`\\`)
+		default:
+			b.WriteByte(v[i])
+		}
+	}
+	return b.String()
+}
+
+// formatValue turns a single value of type t back into the text it is
+// parsed from.
+func formatValue(t ParameterType, v interface{}) string {
+	switch e := v.(type) {
+	case string:
+		return e
+	case int:
+		return strconv.Itoa(e)
+	case float64:
+		return strconv.FormatFloat(e, 'g', -1, 64)
+	case time.Time:
+		if t == TypeDate {
+			return e.Format("Jan-02-2006")
+		}
+		return e.Format(time.Kitchen)
+	case time.Duration:
+		return e.String()
+	case mail.Address:
+		return e.String()
+	case url.URL:
+		return formatURL(e)
+	case net.IP:
+		return e.String()
+	case bool:
+		return strconv.FormatBool(e)
+	}
+	return ""
+}
+
+// formatURL is u.String() except that a path given in a form String
+// would escape, and so parsed into RawPath, is written as it was given.
+func formatURL(u url.URL) string {
+	full := u.String()
+	if u.RawPath == "" {
+		return full
+	}
+	t := u
+	t.Path, t.RawPath, t.RawQuery, t.ForceQuery = "", "", "", false
+	prefix := t.String()
+	esc := u.EscapedPath()
+	if !strings.HasPrefix(full, prefix) || !strings.HasPrefix(full[len(prefix):], esc) {
+		return full
+	}
+	return prefix + u.RawPath + full[len(prefix)+len(esc):]
+}
+
+// JoinArgs quotes args for a POSIX shell and joins them with blanks. It
+// is the reverse of SplitArgs.
+func JoinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = quoteArg(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func quoteArg(a string) string {
+	if a == "" {
+		return "''"
+	}
+	safe := true
+	for _, c := range a {
+		if !strings.ContainsRune("_-+=:,./@%", c) &&
+			!(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return a
+	}
+	return "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+}
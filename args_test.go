@@ -0,0 +1,85 @@
+package boa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArgsRoundTrip(t *testing.T) {
+	cases := []struct {
+		typ  ParameterType
+		sep  string
+		args []string // values only, the item name is put in front
+	}{
+		{TypeBool, "", nil},
+		{TypeString, "", []string{"x -y"}},
+		{TypeStringSlice, "", []string{"a", "-b", ""}},
+		{TypeStringSlice, ",", []string{`a\,b,--,`, "--,x"}},
+		{TypeStringSlice, ",", []string{"--,"}},
+		{TypeInt, "", []string{"-7"}},
+		{TypeIntSlice, "", []string{"1", "-2"}},
+		{TypeIntSlice, ",", []string{"1,-2", "3"}},
+		{TypeFloat, "", []string{"1.5"}},
+		{TypeFloatSlice, ",", []string{"1.5,-2", "-3"}},
+		{TypeTime, "", []string{"3:04PM"}},
+		{TypeTimeSlice, "", []string{"3:04PM", "11:00AM"}},
+		{TypeTimeDuration, "", []string{"1h30m"}},
+		{TypeTimeDurationSlice, ",", []string{"1s,-2m"}},
+		{TypeDate, "", []string{"Jan-02-2006"}},
+		{TypeDateSlice, "", []string{"Jan-02-2006", "Feb-03-2007"}},
+		{TypePath, "", []string{"/tmp/a b"}},
+		{TypePathSlice, ":", []string{"/usr/bin:/bin"}},
+		{TypeURL, "", []string{"http://example.com/a%2Fb?q=1"}},
+		{TypeURLSlice, "", []string{"http://example.com", "https://x.org/y"}},
+		{TypeIPv4, "", []string{"10.0.0.1"}},
+		{TypeIPv4Slice, ",", []string{"10.0.0.1,127.0.0.1"}},
+		{TypeEmail, "", []string{"Joe <joe@example.com>"}},
+		{TypeEmailSlice, "", []string{"joe@example.com", "ann@example.com"}},
+		{TypePhone, "", []string{"+1 555 0100"}},
+		{TypePhoneSlice, "", []string{"555-0100", "555-0101"}},
+		{TypeStringMap, "", []string{"a=1", "b=--x", "c="}},
+		{TypeStringMap, ",", []string{`a=x\,y,b=2`, "c=--"}},
+		{TypeIntMap, ",", []string{"a=1,b=-2"}},
+	}
+
+	seen := make(map[ParameterType]bool)
+	for _, tc := range cases {
+		seen[tc.typ] = true
+		count := 1
+		switch {
+		case tc.typ == TypeBool:
+			count = 0
+		case tc.typ.IsSlice() || tc.typ.IsMap():
+			count = ZeroOrMore
+		}
+		items := map[string]CmdLineItem{
+			"--item":    {Id: 1, Name: "--item", IsFlag: true, ParamType: tc.typ, ParamCount: count, Separator: tc.sep},
+			"--verbose": {Id: 2, Name: "--verbose", IsFlag: true},
+		}
+		p := NewParser(items)
+		args := append([]string{"--item"}, tc.args...)
+		cli := p.Parse(append(args, "--verbose"))
+		if cli.HasErrors() {
+			t.Errorf("%s %q: errors: %s", TypeToString(tc.typ), args, cli.Errors())
+			continue
+		}
+
+		again := cli.Args()
+		cli2 := p.Parse(again)
+		if cli2.HasErrors() {
+			t.Errorf("%s %q rendered as %q: errors: %s", TypeToString(tc.typ), args, again, cli2.Errors())
+			continue
+		}
+		for _, name := range []string{"--item", "--verbose"} {
+			if v, v2 := cli.Items[name].Value, cli2.Items[name].Value; !reflect.DeepEqual(v, v2) {
+				t.Errorf("%s %q rendered as %q: %s = %#v, want %#v", TypeToString(tc.typ), args, again, name, v2, v)
+			}
+		}
+	}
+
+	for typ := TypeBool; typ <= TypeIntMap; typ++ {
+		if !seen[typ] {
+			t.Errorf("no round trip case for %s", TypeToString(typ))
+		}
+	}
+}
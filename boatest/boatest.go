@@ -0,0 +1,279 @@
+// Package boatest helps test command line apps built with boa. It runs
+// table driven command lines against a Parser, checks that the examples
+// in a schema still parse, compares rendered output with golden files
+// and runs handlers with their own stdin, stdout, stderr and environment.
+//
+//	func TestArgs(t *testing.T) {
+//		boatest.RunJSON(t, schema, []boatest.Case{
+//			{Name: "count", Line: "run --count 3", Want: map[string]interface{}{"--count": 3}},
+//			{Name: "bad", Args: []string{"--count", "x"}, Errors: []boa.ParseErrCode{boa.BeNotAnInt}},
+//		})
+//	}
+package boatest
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/westarver/boa"
+)
+
+// update is namespaced so it does not clash with an -update flag of the
+// package under test.
+var update = flag.Bool("boatest.update", false, "rewrite the golden files used by boatest.Golden")
+
+// Case is one command line and what parsing it must give. Args is used
+// when it is not nil, otherwise Line is split the way boa.ParseString
+// splits it.
+type Case struct {
+	Name string
+	Args []string
+	Line string
+
+	Want    map[string]interface{}         // item name to Value
+	Sources map[string]boa.Source          // item name to where its value came from
+	Errors  []boa.ParseErrCode             // codes of all errors in any order, nil means none
+	Unknown []string                       // args collected by the unknown policy
+	Check   func(t testing.TB, c *boa.CLI) // more checks, called after the others
+}
+
+// Run parses every case with p and reports a failure for each way the
+// result differs from the case.
+func Run(t *testing.T, p *boa.Parser, cases []Case) {
+	t.Helper()
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Helper()
+			Check(t, parse(p, tc), tc)
+		})
+	}
+}
+
+// RunJSON is Run for a schema in the format boa.FromJSON reads.
+func RunJSON(t *testing.T, schema []byte, cases []Case, opts ...boa.Option) {
+	t.Helper()
+	p, err := boa.NewParserFromJSON(schema, opts...)
+	if err != nil {
+		t.Fatalf("boatest: schema: %v", err)
+	}
+	Run(t, p, cases)
+}
+
+// Examples parses every example in the AppInfo and items of p, as boa
+// documents them, and fails each one that gives errors. An example starts
+// with the application name, which is dropped when it is the first word.
+func Examples(t *testing.T, p *boa.Parser) {
+	t.Helper()
+	app := p.App()
+	examples := app.Examples
+	items := p.Items()
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if items[names[i]].Id != items[names[j]].Id {
+			return items[names[i]].Id < items[names[j]].Id
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		examples = append(examples, items[name].Examples...)
+	}
+
+	for _, ex := range examples {
+		ex := ex
+		t.Run(ex.Line, func(t *testing.T) {
+			t.Helper()
+			args, err := boa.SplitArgs(ex.Line)
+			if err != nil {
+				t.Fatalf("example %q: %v", ex.Line, err)
+			}
+			if len(args) > 0 && app.Name != "" && args[0] == app.Name {
+				args = args[1:]
+			}
+			if cli := p.Parse(args); cli.HasErrors() {
+				t.Errorf("example %q does not parse:\n%s", ex.Line, cli.Errors())
+			}
+		})
+	}
+}
+
+// ExamplesJSON is Examples for a schema in the format boa.FromJSON reads.
+func ExamplesJSON(t *testing.T, schema []byte, opts ...boa.Option) {
+	t.Helper()
+	p, err := boa.NewParserFromJSON(schema, opts...)
+	if err != nil {
+		t.Fatalf("boatest: schema: %v", err)
+	}
+	Examples(t, p)
+}
+
+func parse(p *boa.Parser, tc Case) *boa.CLI {
+	if tc.Args != nil {
+		return p.Parse(tc.Args)
+	}
+	return p.ParseString(tc.Line)
+}
+
+// Check compares cli with what tc wants.
+func Check(t testing.TB, cli *boa.CLI, tc Case) {
+	t.Helper()
+	got := Codes(cli)
+	want := append([]boa.ParseErrCode(nil), tc.Errors...)
+	sortCodes(got)
+	sortCodes(want)
+	if len(got) != len(want) || (len(got) > 0 && !reflect.DeepEqual(got, want)) {
+		t.Errorf("errors = %v, want %v\n%s", got, want, cli.Errors())
+	}
+
+	for name, w := range tc.Want {
+		it, ok := cli.Items[name]
+		if !ok {
+			t.Errorf("%s: not found, want %#v", name, w)
+			continue
+		}
+		if !reflect.DeepEqual(it.Value, w) {
+			t.Errorf("%s = %#v, want %#v", name, it.Value, w)
+		}
+	}
+	for name, w := range tc.Sources {
+		if s := cli.Source(name); s != w {
+			t.Errorf("%s: source = %v, want %v", name, s, w)
+		}
+	}
+	if tc.Unknown != nil && !reflect.DeepEqual(cli.Unknown(), tc.Unknown) {
+		t.Errorf("unknown = %q, want %q", cli.Unknown(), tc.Unknown)
+	}
+	if tc.Check != nil {
+		tc.Check(t, cli)
+	}
+}
+
+// Codes returns the code of every boa.ParseError reported by cli, those
+// of the items included.
+func Codes(cli *boa.CLI) []boa.ParseErrCode {
+	var codes []boa.ParseErrCode
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case nil:
+		case boa.ParseError:
+			codes = append(codes, e.Code)
+		case *boa.ParseError:
+			codes = append(codes, e.Code)
+		case interface{ Unwrap() []error }:
+			for _, err := range e.Unwrap() {
+				walk(err)
+			}
+		default:
+			var pe boa.ParseError
+			if errors.As(err, &pe) {
+				codes = append(codes, pe.Code)
+			}
+		}
+	}
+	for _, err := range cli.Errs {
+		walk(err)
+	}
+	for _, it := range cli.Items {
+		for _, err := range it.Errors {
+			walk(err)
+		}
+	}
+	return codes
+}
+
+func sortCodes(c []boa.ParseErrCode) {
+	sort.Slice(c, func(i, j int) bool { return c[i] < c[j] })
+}
+
+// Golden compares got with testdata/<name>.golden. With -boatest.update
+// the file is written instead.
+func Golden(t testing.TB, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -boatest.update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from %s:\ngot:\n%s\nwant:\n%s", name, path, got, want)
+	}
+}
+
+// Output is what a handler run by Exec wrote.
+type Output struct {
+	Stdout string
+	Stderr string
+}
+
+// Exec runs fn with stdin reading from the string given, stdout and
+// stderr captured and env added to the environment. The process wide
+// os.Stdin, os.Stdout and os.Stderr are swapped while fn runs, so tests
+// using Exec must not run in parallel.
+func Exec(t testing.TB, stdin string, env map[string]string, fn func() error) (Output, error) {
+	t.Helper()
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+
+	inR, inW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldIn, oldOut, oldErr := os.Stdin, os.Stdout, os.Stderr
+	os.Stdin, os.Stdout, os.Stderr = inR, outW, errW
+
+	var wg sync.WaitGroup
+	var out Output
+	read := func(r *os.File, s *string) {
+		defer wg.Done()
+		b, _ := io.ReadAll(r)
+		*s = string(b)
+	}
+	wg.Add(2)
+	go read(outR, &out.Stdout)
+	go read(errR, &out.Stderr)
+	go func() {
+		_, _ = io.WriteString(inW, stdin)
+		inW.Close()
+	}()
+
+	ferr := fn()
+
+	os.Stdin, os.Stdout, os.Stderr = oldIn, oldOut, oldErr
+	outW.Close()
+	errW.Close()
+	wg.Wait()
+	inR.Close()
+	outR.Close()
+	errR.Close()
+	return out, ferr
+}
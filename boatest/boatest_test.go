@@ -0,0 +1,99 @@
+package boatest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/westarver/boa"
+)
+
+// a package using boatest may have an -update flag of its own
+var _ = flag.Bool("update", false, "rewrite this package's own files")
+
+var schema = []byte(`{"commands": [
+	{"Id": 1, "Name": "--count", "Alias": "-c", "IsFlag": true, "ParamType": 3, "ParamCount": 1, "ShortHelp": "--count: how many"},
+	{"Id": 2, "Name": "--verbose", "Alias": "-v", "IsFlag": true, "ShortHelp": "--verbose: say more"}
+]}`)
+
+func TestRun(t *testing.T) {
+	RunJSON(t, schema, []Case{
+		{Name: "count", Line: "--count 3 -v", Want: map[string]interface{}{"--count": 3, "--verbose": true},
+			Sources: map[string]boa.Source{"--count": boa.SourceArgs}},
+		{Name: "bad", Args: []string{"--count", "x"}, Errors: []boa.ParseErrCode{boa.BeNotAnInt}},
+	})
+}
+
+// recorder is a testing.TB that keeps its failures instead of failing.
+type recorder struct {
+	testing.TB
+	errs []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func TestCheckFails(t *testing.T) {
+	p, err := boa.NewParserFromJSON(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := p.Parse([]string{"--count", "x"})
+	for _, tc := range []Case{
+		{Name: "no errors wanted"},
+		{Name: "wrong value", Errors: []boa.ParseErrCode{boa.BeNotAnInt}, Want: map[string]interface{}{"--count": 3}},
+		{Name: "wrong code", Errors: []boa.ParseErrCode{boa.BeInvalidCommand}},
+	} {
+		r := &recorder{TB: t}
+		Check(r, cli, tc)
+		if len(r.errs) == 0 {
+			t.Errorf("%s: Check did not fail", tc.Name)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	p, err := boa.NewParserFromJSON(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Golden(t, "usage", p.Parse(nil).Usage())
+}
+
+var exampleSchema = []byte(`{"app": {"Name": "tool", "Examples": [{"Line": "tool --count 3"}]},
+"commands": [
+	{"Id": 1, "Name": "--count", "Alias": "-c", "IsFlag": true, "ParamType": 3, "ParamCount": 1, "ShortHelp": "--count: how many",
+	 "Examples": [{"Line": "tool -c 2 -v"}, {"Line": "tool --count lots"}]},
+	{"Id": 2, "Name": "--verbose", "Alias": "-v", "IsFlag": true, "ShortHelp": "--verbose: say more"}
+]}`)
+
+// TestExamples runs itself again with BOATEST_EXAMPLES set, as Examples
+// fails a *testing.T of its own that a recorder cannot stand in for.
+func TestExamples(t *testing.T) {
+	if os.Getenv("BOATEST_EXAMPLES") != "" {
+		ExamplesJSON(t, exampleSchema)
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestExamples$", "-test.v")
+	cmd.Env = append(os.Environ(), "BOATEST_EXAMPLES=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Examples passed an example that does not parse:\n%s", out)
+	}
+	for _, want := range []string{
+		"--- PASS: TestExamples/tool_--count_3",
+		"--- PASS: TestExamples/tool_-c_2_-v",
+		"--- FAIL: TestExamples/tool_--count_lots",
+		`example "tool --count lots" does not parse`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("output has no %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package boatest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/westarver/boa"
+)
+
+// Fuzz runs the native fuzzer over command lines for p. Each input is a
+// list of arguments joined with NUL bytes; seeds are added to the corpus.
+// Every command line must pass Invariants and RoundTrip.
+//
+//	func FuzzArgs(f *testing.F) {
+//		p, _ := boa.NewParserFromJSON(schema)
+//		boatest.Fuzz(f, p, []string{"deploy", "--tags", "a", "b"})
+//	}
+func Fuzz(f *testing.F, p *boa.Parser, seeds ...[]string) {
+	for _, s := range seeds {
+		f.Add(strings.Join(s, "\x00"))
+	}
+	f.Fuzz(func(t *testing.T, data string) {
+		args := splitNUL(data)
+		Invariants(t, p, args)
+		RoundTrip(t, p, args)
+	})
+}
+
+// FuzzSchemas runs the native fuzzer over random schemas as well as
+// random command lines. The seed picks the schema and the start of the
+// command line, data adds arguments the fuzzer chooses.
+func FuzzSchemas(f *testing.F) {
+	for seed := int64(0); seed < 8; seed++ {
+		f.Add(seed, "")
+	}
+	f.Add(int64(1), "--\x00-x\x00=\x00a=b=c")
+	f.Fuzz(func(t *testing.T, seed int64, data string) {
+		r := rand.New(rand.NewSource(seed))
+		items := RandomItems(r)
+		args := append(RandomArgs(r, items), splitNUL(data)...)
+		p := boa.NewParser(items)
+		Invariants(t, p, args)
+		RoundTrip(t, p, args)
+	})
+}
+
+func splitNUL(data string) []string {
+	if data == "" {
+		return nil
+	}
+	return strings.Split(data, "\x00")
+}
+
+// Invariants parses args with p and checks what must hold for any
+// command line whatever the schema:
+//   - parsing does not panic
+//   - the argument an error points at is one of args
+//   - when nothing is reported, every argument naming an item was either
+//     parsed as that item or taken as the value of the item before it
+//
+// The last check assumes p uses the default dialect. Invariants returns
+// the parsed CLI for further checks.
+func Invariants(t testing.TB, p *boa.Parser, args []string) (cli *boa.CLI) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("parse of %q panicked: %v", args, r)
+		}
+	}()
+	cli = p.Parse(append([]string(nil), args...))
+
+	for _, err := range cli.Errs {
+		if pe, ok := err.(boa.ParseError); ok && pe.Arg >= len(args) {
+			t.Errorf("parse of %q: error points at arg %d: %v", args, pe.Arg, pe)
+		}
+	}
+	if cli.HasErrors() || len(cli.Unknown()) > 0 || len(cli.Passthrough()) > 0 {
+		return cli
+	}
+
+	items := p.Items()
+	names := make(map[string]string)
+	for name, it := range items {
+		names[name] = name
+		for _, a := range it.AllAliases() {
+			names[a] = name
+		}
+	}
+
+	owed := 0 // values the last item takes whatever they look like
+	var last boa.CmdLineItem
+	for _, a := range args {
+		name, isName := names[a]
+		switch {
+		case isName && isChildOf(last, name):
+			continue
+		case owed > 0:
+			owed--
+			continue
+		case !isName:
+			continue
+		}
+		if _, ok := cli.Items[name]; !ok {
+			t.Errorf("parse of %q: %s was neither parsed nor reported", args, a)
+		}
+		last = items[name]
+		owed = takes(last)
+	}
+	return cli
+}
+
+// RoundTrip parses args with p, renders the result with CLI.Args and
+// checks that parsing that again gives the same items, values and sources.
+// Command lines that do not parse cleanly are skipped.
+func RoundTrip(t testing.TB, p *boa.Parser, args []string) {
+	t.Helper()
+	cli := p.Parse(append([]string(nil), args...))
+	if cli.HasErrors() {
+		return
+	}
+	again := cli.Args()
+	cli2 := p.Parse(append([]string(nil), again...))
+	if cli2.HasErrors() {
+		t.Fatalf("%q rendered as %q does not parse: %s", args, again, cli2.Errors())
+	}
+	if len(cli.Items) != len(cli2.Items) {
+		t.Errorf("%q rendered as %q: %d items, want %d", args, again, len(cli2.Items), len(cli.Items))
+	}
+	for name, it := range cli.Items {
+		it2 := cli2.Items[name]
+		if !reflect.DeepEqual(it.Value, it2.Value) || it.Source != it2.Source {
+			t.Errorf("%q rendered as %q: %s = %#v (%v), want %#v (%v)",
+				args, again, name, it2.Value, it2.Source, it.Value, it.Source)
+		}
+	}
+	if !reflect.DeepEqual(cli.Unknown(), cli2.Unknown()) || !reflect.DeepEqual(cli.Passthrough(), cli2.Passthrough()) {
+		t.Errorf("%q rendered as %q: unknown or passthrough args differ", args, again)
+	}
+}
+
+func isChildOf(it boa.CmdLineItem, name string) bool {
+	for _, c := range it.ChNames {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// takes returns how many of the arguments after an item it takes as
+// values even when they name other items.
+func takes(it boa.CmdLineItem) int {
+	switch {
+	case it.ParamCount == 0, it.ParamType == boa.TypeBool,
+		it.ParamType.IsSlice(), it.ParamType.IsMap():
+		return 0
+	case it.ParamCount > 1:
+		return it.ParamCount
+	}
+	return 1
+}
+
+var randTypes = []boa.ParameterType{
+	boa.TypeBool, boa.TypeString, boa.TypeStringSlice, boa.TypeInt,
+	boa.TypeIntSlice, boa.TypeFloat, boa.TypeFloatSlice, boa.TypeTime,
+	boa.TypeTimeDuration, boa.TypeDate, boa.TypeURL, boa.TypeIPv4,
+	boa.TypeIPv4Slice, boa.TypeEmail, boa.TypePhone, boa.TypeStringMap,
+	boa.TypeIntMap,
+}
+
+var randCounts = []int{1, 1, boa.OneOrNone, 2, boa.ZeroOrMore, boa.OneOrMore}
+
+var randValues = []string{
+	"", "x", "0", "42", "-7", "1.5", "--", "-", "=", "a=b", "a=1", "k=v=w",
+	"3:04PM", "1h30m", "Jan-02-2006", "http://example.com/a", "10.0.0.1",
+	"joe@example.com", "+1 555 0100", "a,b,c", "@file", "-abc", "--x=y",
+}
+
+// RandomItems builds a valid schema of a few commands and flags of
+// random types and arities, some commands having sub commands.
+func RandomItems(r *rand.Rand) map[string]boa.CmdLineItem {
+	items := make(map[string]boa.CmdLineItem)
+	n := 1 + r.Intn(8)
+	for i := 0; i < n; i++ {
+		it := boa.CmdLineItem{Id: i + 1, ParamType: randTypes[r.Intn(len(randTypes))]}
+		if r.Intn(2) == 0 {
+			it.Name = fmt.Sprintf("--flag%d", i)
+			it.IsFlag = true
+		} else {
+			it.Name = fmt.Sprintf("cmd%d", i)
+		}
+		if r.Intn(3) == 0 {
+			it.Alias = "-" + string(rune('a'+i))
+		}
+		if it.ParamType != boa.TypeBool {
+			it.ParamCount = randCounts[r.Intn(len(randCounts))]
+		}
+		if r.Intn(4) == 0 {
+			it.DefaultValue = randValues[r.Intn(len(randValues))]
+		}
+		if it.ParamType == boa.TypeString && r.Intn(4) == 0 {
+			it.Choices = []string{"x", "y"}
+		}
+		if it.ParamType.IsSlice() && r.Intn(4) == 0 {
+			it.Separator = ","
+		}
+		items[it.Name] = it
+	}
+
+	// hang some of the flags under a command
+	names := sortedNames(items)
+	for _, name := range names {
+		it := items[name]
+		if it.IsFlag || r.Intn(2) == 0 {
+			continue
+		}
+		for _, ch := range names {
+			c := items[ch]
+			if c.IsFlag && c.ParName == "" && r.Intn(2) == 0 {
+				c.ParName = name
+				it.ChNames = append(it.ChNames, ch)
+				items[ch] = c
+			}
+		}
+		items[name] = it
+	}
+	return items
+}
+
+// sortedNames keeps the random choices the same for the same seed.
+func sortedNames(items map[string]boa.CmdLineItem) []string {
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RandomArgs returns a command line of names, aliases and values that
+// may or may not fit items.
+func RandomArgs(r *rand.Rand, items map[string]boa.CmdLineItem) []string {
+	var words []string
+	for _, name := range sortedNames(items) {
+		words = append(words, name)
+		words = append(words, items[name].AllAliases()...)
+	}
+	n := r.Intn(10)
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if r.Intn(2) == 0 && len(words) > 0 {
+			args = append(args, words[r.Intn(len(words))])
+			continue
+		}
+		args = append(args, randValues[r.Intn(len(randValues))])
+	}
+	return args
+}
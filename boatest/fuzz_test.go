@@ -0,0 +1,33 @@
+package boatest_test
+
+import (
+	"testing"
+
+	"github.com/westarver/boa"
+	"github.com/westarver/boa/boatest"
+)
+
+func FuzzSchemas(f *testing.F) {
+	boatest.FuzzSchemas(f)
+}
+
+func FuzzArgs(f *testing.F) {
+	p := boa.NewParser(map[string]boa.CmdLineItem{
+		"deploy":    {Id: 1, Name: "deploy", ParamType: boa.TypeString, ParamCount: boa.OneOrNone, ChNames: []string{"--now"}},
+		"--now":     {Id: 2, Name: "--now", Alias: "-n", IsFlag: true, ParName: "deploy"},
+		"--count":   {Id: 3, Name: "--count", Alias: "-c", IsFlag: true, ParamType: boa.TypeInt, ParamCount: boa.OneOrNone},
+		"--verbose": {Id: 4, Name: "--verbose", Alias: "-v", IsFlag: true},
+		"--tags":    {Id: 5, Name: "--tags", IsFlag: true, ParamType: boa.TypeStringSlice, ParamCount: boa.ZeroOrMore, Separator: ","},
+		"--size":    {Id: 6, Name: "--size", IsFlag: true, ParamType: boa.TypeInt, ParamCount: 2},
+		"--env":     {Id: 7, Name: "--env", IsFlag: true, ParamType: boa.TypeStringMap, ParamCount: boa.OneOrMore},
+		"--level":   {Id: 8, Name: "--level", IsFlag: true, ParamType: boa.TypeInt, ParamCount: boa.OneOrNone, DefaultValue: "1"},
+	})
+	boatest.Fuzz(f, p,
+		[]string{"deploy", "-n", "prod", "--tags", "a", "b"},
+		[]string{"--count", "--verbose"},
+		[]string{"--count=3", "-vc", "4", "--", "x"},
+		[]string{"--size", "1", "2", "--env", "k=v", "k2=v2"},
+		[]string{"--tags", "a,b", "--level"},
+		[]string{"deploy", "--", "--verbose"},
+	)
+}
@@ -0,0 +1,156 @@
+package boa
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Built-ins, as Builtin reports them.
+const (
+	BuiltinHelp    = "help"
+	BuiltinVersion = "version"
+)
+
+// WithHelp turns on the built-in -h and --help flags, which print the
+// usage of the command they follow, or of everything at the top level,
+// and the help command, as in 'app help [command]'. Items of the schema
+// with the same names take precedence.
+func WithHelp() Option {
+	return func(C *CLI) {
+		C.conf.help = true
+	}
+}
+
+// WithVersion turns on the built-in --version flag, which prints the
+// Version, Commit and BuildDate of the AppInfo.
+func WithVersion() Option {
+	return func(C *CLI) {
+		C.conf.version = true
+	}
+}
+
+// WithOutput sets where the built-ins print, os.Stdout by default.
+func WithOutput(w io.Writer) Option {
+	return func(C *CLI) {
+		C.conf.out = w
+	}
+}
+
+// Builtin returns the built-in the command line asked for, BuiltinHelp
+// or BuiltinVersion, or "" when it asked for none. The built-in has been
+// printed already and required items were not checked, so a program
+// usually just exits when it is set.
+func (C *CLI) Builtin() string {
+	return C.builtin
+}
+
+// builtinAt checks whether the token a at position n asks for a built-in
+// and records it. level is the last command seen before it.
+func (C *CLI) builtinAt(ix *itemIndex, a string, n int, rest []string, level *CmdLineItem) bool {
+	if ix.isKnown(a) {
+		return false
+	}
+	switch {
+	case C.conf.help && (a == "-h" || a == "--help"):
+		C.builtin = BuiltinHelp
+		if level != nil {
+			C.topic = level.Name
+		}
+	case C.conf.help && a == "help" && n == 0:
+		C.builtin = BuiltinHelp
+		if len(rest) > 0 {
+			C.topic = rest[0]
+			if _, ok := ix.lookup(C.topic); !ok {
+				C.SetError(Errorf(BeInvalidCommand, C.topic))
+			}
+		}
+	case C.conf.version && a == "--version":
+		C.builtin = BuiltinVersion
+	default:
+		return false
+	}
+	return true
+}
+
+// isBuiltin reports whether a is a built-in flag that is turned on and
+// not taken by an item. Values end at one just as they end at an item.
+func (ix *itemIndex) isBuiltin(a string) bool {
+	switch {
+	case ix.isKnown(a):
+		return false
+	case a == "-h" || a == "--help":
+		return ix.help
+	case a == "--version":
+		return ix.version
+	}
+	return false
+}
+
+// printBuiltin prints what the built-in asked for to w.
+func (C *CLI) printBuiltin(w io.Writer) {
+	if w == nil {
+		w = os.Stdout
+	}
+	switch C.builtin {
+	case BuiltinHelp:
+		if C.topic == "" {
+			fmt.Fprint(w, C.Usage())
+			return
+		}
+		if u := C.CommandUsage(C.topic); u != "" {
+			fmt.Fprint(w, u)
+			return
+		}
+		fmt.Fprintln(w, C.message(Errorf(BeInvalidCommand, C.topic), C.errorStyle()))
+	case BuiltinVersion:
+		fmt.Fprintln(w, C.VersionString())
+	}
+}
+
+// CommandUsage returns the help of command and of the items under it.
+func (C *CLI) CommandUsage(command string) string {
+	if C.index == nil {
+		return C.Help(command)
+	}
+	command = C.index.canonical(command)
+	cmd, ok := C.index.cmds[command]
+	if !ok {
+		return ""
+	}
+
+	var b strings.Builder
+	heading := "Usage of " + strings.TrimSpace(C.Application+" "+command) + ":"
+	if th := C.conf.styles(); th != nil {
+		heading = th.Heading.paint(heading)
+	}
+	b.WriteString(heading + "\n")
+	for _, name := range C.helpOrder() {
+		it := C.index.cmds[name]
+		if name == command || it.ParName == command || contains(cmd.ChNames, name) {
+			b.WriteString(strings.TrimRight(C.itemHelp(name), "\n") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// VersionString is the line --version prints: the application name and
+// the Version, Commit and BuildDate of the AppInfo.
+func (C *CLI) VersionString() string {
+	s := first(C.Application, C.app.Name)
+	if C.app.Version != "" {
+		s = strings.TrimSpace(s + " version " + C.app.Version)
+	}
+	var extra []string
+	if C.app.Commit != "" {
+		extra = append(extra, "commit "+C.app.Commit)
+	}
+	if C.app.BuildDate != "" {
+		extra = append(extra, "built "+C.app.BuildDate)
+	}
+	if len(extra) > 0 {
+		s += " (" + strings.Join(extra, ", ") + ")"
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,46 @@
+package boa
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuiltinEndsValues(t *testing.T) {
+	items := map[string]CmdLineItem{
+		"deploy": {Id: 1, Name: "deploy", ParamType: TypeString, ParamCount: 1, ShortHelp: "deploy: ship it"},
+		"--tags": {Id: 2, Name: "--tags", IsFlag: true, ParamType: TypeStringSlice, ParamCount: OneOrMore},
+		"--size": {Id: 3, Name: "--size", IsFlag: true, ParamType: TypeInt, ParamCount: 2},
+	}
+	for _, tc := range []struct {
+		args    []string
+		builtin string
+		topic   string
+	}{
+		{[]string{"deploy", "--help"}, BuiltinHelp, "deploy"},
+		{[]string{"deploy", "-h"}, BuiltinHelp, "deploy"},
+		{[]string{"--tags", "a", "--help"}, BuiltinHelp, ""},
+		{[]string{"--tags", "--version"}, BuiltinVersion, ""},
+		{[]string{"--size", "1", "--version"}, BuiltinVersion, ""},
+	} {
+		var out bytes.Buffer
+		cli := NewParser(items, WithHelp(), WithVersion(), WithOutput(&out)).Parse(tc.args)
+		if cli.Builtin() != tc.builtin {
+			t.Errorf("%q: builtin = %q, want %q", tc.args, cli.Builtin(), tc.builtin)
+		}
+		if cli.topic != tc.topic {
+			t.Errorf("%q: topic = %q, want %q", tc.args, cli.topic, tc.topic)
+		}
+		if v := cli.Items["deploy"].Value; v != nil {
+			t.Errorf("%q: deploy = %q, the built-in was taken as its value", tc.args, v)
+		}
+		if tags, _ := cli.StringSlice("--tags"); len(tags) > 1 {
+			t.Errorf("%q: --tags = %q, the built-in was taken as a value", tc.args, tags)
+		}
+	}
+
+	// without the built-ins they are values like any other
+	cli := NewParser(items).Parse([]string{"deploy", "--help"})
+	if s, _ := cli.String("deploy"); s != "--help" {
+		t.Errorf("deploy = %q, want --help", s)
+	}
+}
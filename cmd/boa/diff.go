@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/westarver/boa"
+)
+
+var errBreaking = errors.New("breaking changes found")
+
+// runDiff implements 'boa diff'. It compares two schemas and prints every
+// change, breaking ones first. It fails when any change is breaking so it
+// can stop a review:
+//
+//	boa diff old.json new.json
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the changes as a JSON array")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("diff: expecting an old and a new schema file")
+	}
+
+	old, err := readSchema(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	cur, err := readSchema(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	changes := boa.Diff(old, cur)
+	if *asJSON {
+		if changes == nil {
+			changes = []boa.Change{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(changes); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+	}
+
+	if boa.HasBreaking(changes) {
+		return errBreaking
+	}
+	return nil
+}
+
+func readSchema(file string) (map[string]boa.CmdLineItem, error) {
+	schema, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	items, err := boa.CollectItemsFromJSON(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/westarver/boa/boatest"
+)
+
+func TestDiffCommand(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want string
+		err  error
+	}{
+		{[]string{"testdata/diff-old.json", "testdata/diff-old.json"}, "", nil},
+		{[]string{"testdata/diff-old.json", "testdata/diff-additive.json"},
+			"additive: --out: alias -O added\nadditive: --quiet: added\n", nil},
+		{[]string{"testdata/diff-old.json", "testdata/diff-breaking.json"},
+			"breaking: --out: alias -o removed\nbreaking: --out: now required\nbreaking: --verbose: removed\nadditive: --quiet: added\n", errBreaking},
+		{[]string{"-json", "testdata/diff-old.json", "testdata/diff-old.json"}, "[]\n", nil},
+		{[]string{"-json", "testdata/diff-old.json", "testdata/diff-additive.json"},
+			`[
+  {
+    "kind": "additive",
+    "item": "--out",
+    "what": "alias -O added"
+  },
+  {
+    "kind": "additive",
+    "item": "--quiet",
+    "what": "added"
+  }
+]
+`, nil},
+	} {
+		out, err := boatest.Exec(t, "", nil, func() error { return runDiff(tc.args) })
+		if err != tc.err {
+			t.Errorf("%q: err = %v, want %v", tc.args, err, tc.err)
+		}
+		if out.Stdout != tc.want {
+			t.Errorf("%q: output\n%s\nwant\n%s", tc.args, out.Stdout, tc.want)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/westarver/boa"
+)
+
+// runGen implements 'boa gen'. It reads a schema in the format FromJSON
+// consumes and writes a Go package with typed option structs, the schema
+// itself, a Parse function and the handlers found in RunCode. It is meant
+// to be driven by go generate:
+//
+//	//go:generate boa gen -o options.go schema.json
+func runGen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	out := fs.String("o", "", "output file (default stdout)")
+	pkg := fs.String("pkg", os.Getenv("GOPACKAGE"), "package name of the generated file")
+	imps := fs.String("import", "", "comma separated extra imports used by RunCode")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("gen: expecting exactly one schema file")
+	}
+	if *pkg == "" {
+		*pkg = "main"
+	}
+
+	file := fs.Arg(0)
+	schema, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	items, err := boa.CollectItemsFromJSON(schema)
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+
+	var extra []string
+	if *imps != "" {
+		extra = strings.Split(*imps, ",")
+	}
+	src, err := generate(items, *pkg, filepath.Base(file), schema, extra)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0644)
+}
+
+// genType describes the Go side of a ParameterType: the type of the
+// generated field, the CLI accessor that fills it and the import it needs.
+type genType struct {
+	goType string
+	getter string
+	imp    string
+}
+
+var genTypes = map[boa.ParameterType]genType{
+	boa.TypeBool:              {"bool", "Bool", ""},
+	boa.TypeString:            {"string", "String", ""},
+	boa.TypeStringSlice:       {"[]string", "StringSlice", ""},
+	boa.TypeInt:               {"int", "Int", ""},
+	boa.TypeIntSlice:          {"[]int", "IntSlice", ""},
+	boa.TypeFloat:             {"float64", "Float", ""},
+	boa.TypeFloatSlice:        {"[]float64", "FloatSlice", ""},
+	boa.TypeTime:              {"time.Time", "Time", "time"},
+	boa.TypeTimeSlice:         {"[]time.Time", "TimeSSlice", "time"},
+	boa.TypeTimeDuration:      {"time.Duration", "TimeDuration", "time"},
+	boa.TypeTimeDurationSlice: {"[]time.Duration", "TimeDurationSlice", "time"},
+	boa.TypeDate:              {"time.Time", "Date", "time"},
+	boa.TypeDateSlice:         {"[]time.Time", "DateSlice", "time"},
+	boa.TypePath:              {"string", "Path", ""},
+	boa.TypePathSlice:         {"[]string", "PathSlice", ""},
+	boa.TypeURL:               {"url.URL", "URL", "net/url"},
+	boa.TypeURLSlice:          {"[]url.URL", "URLSlice", "net/url"},
+	boa.TypeIPv4:              {"net.IP", "IPv4", "net"},
+	boa.TypeIPv4Slice:         {"[]net.IP", "IPv4Slice", "net"},
+	boa.TypeEmail:             {"mail.Address", "Email", "net/mail"},
+	boa.TypeEmailSlice:        {"[]mail.Address", "EmailSlice", "net/mail"},
+	boa.TypePhone:             {"string", "String", ""},
+	boa.TypePhoneSlice:        {"[]string", "StringSlice", ""},
+	boa.TypeStringMap:         {"map[string]string", "StringMap", ""},
+	boa.TypeIntMap:            {"map[string]int", "IntMap", ""},
+}
+
+type generator struct {
+	children map[string][]boa.CmdLineItem // keyed by parent name, "" is the top level
+	idents   map[string]string            // item name to Go identifier
+	imports  map[string]bool
+	buf      bytes.Buffer
+}
+
+func generate(items map[string]boa.CmdLineItem, pkg, file string, schema []byte, imports []string) ([]byte, error) {
+	g := generator{
+		children: make(map[string][]boa.CmdLineItem),
+		idents:   make(map[string]string),
+		imports:  make(map[string]bool),
+	}
+	for _, imp := range imports {
+		if imp = strings.TrimSpace(imp); imp != "" {
+			g.imports[imp] = true
+		}
+	}
+
+	var sorted []boa.CmdLineItem
+	for _, it := range items {
+		if it.Name == boa.AppDataName() {
+			continue
+		}
+		sorted = append(sorted, it)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Id != sorted[j].Id {
+			return sorted[i].Id < sorted[j].Id
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	used := map[string]bool{"Value": true}
+	for _, it := range sorted {
+		id := goIdent(it.Name)
+		for n := 2; used[id]; n++ {
+			id = goIdent(it.Name) + strconv.Itoa(n)
+		}
+		used[id] = true
+		g.idents[it.Name] = id
+
+		parent := it.ParName
+		if _, ok := items[parent]; !ok || parent == it.Name {
+			parent = ""
+		}
+		g.children[parent] = append(g.children[parent], it)
+	}
+
+	g.printf("const Schema = %s\n\n", quoteSchema(schema))
+
+	g.printf("// Options holds the typed values of the top level items.\n")
+	g.printStruct("Options", nil, g.children[""])
+	g.printCommandStructs(g.children[""], map[string]bool{})
+
+	g.printf("// NewParser builds a Parser for Schema with opts, such as boa.WithHelp.\n")
+	g.printf("func NewParser(opts ...boa.Option) *boa.Parser {\n")
+	g.printf("p, _ := boa.NewParserFromJSON([]byte(Schema), opts...) // Schema was checked by boa gen\n")
+	g.printf("return p\n}\n\n")
+	g.printf("// parser is the Parser Parse uses when it is given no options.\n")
+	g.printf("var parser = NewParser()\n\n")
+	g.printf("// Parse parses args against Schema and copies the values found\n")
+	g.printf("// into a new Options. Errors are reported through the returned CLI.\n")
+	g.printf("// A Parser is built for the call when opts are given; to parse many\n")
+	g.printf("// command lines with the same options use ParseWith.\n")
+	g.printf("func Parse(args []string, opts ...boa.Option) (*Options, *boa.CLI) {\n")
+	g.printf("p := parser\n")
+	g.printf("if len(opts) > 0 {\np = NewParser(opts...)\n}\n")
+	g.printf("return ParseWith(p, args)\n}\n\n")
+	g.printf("// ParseWith is Parse with a Parser from NewParser.\n")
+	g.printf("func ParseWith(p *boa.Parser, args []string) (*Options, *boa.CLI) {\n")
+	g.printf("cli := p.Parse(args)\n")
+	g.printf("opts := &Options{}\n")
+	g.printFill("opts", g.children[""], map[string]bool{})
+	g.printf("return opts, cli\n}\n\n")
+
+	g.printf("// Run calls the handler of every item present on the command line,\n")
+	g.printf("// parents before children, and stops at the first error.\n")
+	g.printf("func Run(opts *Options, cli *boa.CLI) error {\n")
+	g.printDispatch("opts", g.children[""], map[string]bool{})
+	g.printf("return nil\n}\n\n")
+	g.printHandlers(sorted)
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by boa gen from %s; DO NOT EDIT.\n\n", file)
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	src.WriteString("import (\n")
+	var imps []string
+	for imp := range g.imports {
+		imps = append(imps, imp)
+	}
+	sort.Strings(imps)
+	for _, imp := range imps {
+		fmt.Fprintf(&src, "%q\n", imp)
+	}
+	src.WriteString("\n\"github.com/westarver/boa\"\n)\n\n")
+	src.Write(g.buf.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not parse, check RunCode: %v", err)
+	}
+	return formatted, nil
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// valueType returns the Go type of the value an item takes; an item
+// defined with no parameters is always a bool.
+func (g *generator) valueType(it boa.CmdLineItem) genType {
+	t, ok := genTypes[it.ParamType]
+	if !ok || it.ParamCount == 0 {
+		t = genTypes[boa.TypeBool]
+	}
+	if it.ParamCount > 1 && !it.ParamType.IsSlice() {
+		t = genType{"[]interface{}", "Tuple", ""}
+	}
+	if t.imp != "" {
+		g.imports[t.imp] = true
+	}
+	return t
+}
+
+func (g *generator) printStruct(name string, cmd *boa.CmdLineItem, fields []boa.CmdLineItem) {
+	g.printf("type %s struct {\n", name)
+	if cmd != nil && cmd.ParamCount != 0 {
+		g.printf("Value %s\n", g.valueType(*cmd).goType)
+	}
+	for _, f := range fields {
+		if help := firstLine(f.ShortHelp); help != "" {
+			g.printf("// %s\n", help)
+		}
+		id := g.idents[f.Name]
+		if !f.IsFlag {
+			g.printf("%s *%sOptions\n", id, id)
+			continue
+		}
+		g.printf("%s %s\n", id, g.valueType(f).goType)
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) printCommandStructs(items []boa.CmdLineItem, seen map[string]bool) {
+	for _, it := range items {
+		if it.IsFlag || seen[it.Name] {
+			continue
+		}
+		seen[it.Name] = true
+		id := g.idents[it.Name]
+		g.printf("// %sOptions holds the values of %s and its sub items.\n", id, it.Name)
+		g.printf("// It is nil in its parent when %s is not on the command line.\n", it.Name)
+		g.printStruct(id+"Options", &it, g.children[it.Name])
+		g.printCommandStructs(g.children[it.Name], seen)
+	}
+}
+
+func (g *generator) printFill(expr string, items []boa.CmdLineItem, seen map[string]bool) {
+	for _, it := range items {
+		if seen[it.Name] {
+			continue
+		}
+		seen[it.Name] = true
+		field := expr + "." + g.idents[it.Name]
+		if it.IsFlag {
+			g.printf("%s, _ = cli.%s(%q)\n", field, g.valueType(it).getter, it.Name)
+			continue
+		}
+		g.printf("if _, ok := cli.Items[%q]; ok {\n", it.Name)
+		g.printf("%s = &%sOptions{}\n", field, g.idents[it.Name])
+		if it.ParamCount != 0 {
+			g.printf("%s.Value, _ = cli.%s(%q)\n", field, g.valueType(it).getter, it.Name)
+		}
+		g.printFill(field, g.children[it.Name], seen)
+		g.printf("}\n")
+	}
+}
+
+func (g *generator) printDispatch(expr string, items []boa.CmdLineItem, seen map[string]bool) {
+	for _, it := range items {
+		if seen[it.Name] {
+			continue
+		}
+		seen[it.Name] = true
+		id := g.idents[it.Name]
+		if it.IsFlag {
+			if it.RunCode == "" {
+				continue
+			}
+			g.printf("if _, ok := cli.Items[%q]; ok {\n", it.Name)
+		} else {
+			g.printf("if %s.%s != nil {\n", expr, id)
+		}
+		g.printf("if err := Run%s(opts, cli); err != nil {\nreturn err\n}\n", id)
+		if !it.IsFlag {
+			g.printDispatch(expr+"."+id, g.children[it.Name], seen)
+		}
+		g.printf("}\n")
+	}
+}
+
+// printHandlers writes one handler per command and per flag that has
+// RunCode. The body of a handler is the RunCode of its item.
+func (g *generator) printHandlers(items []boa.CmdLineItem) {
+	for _, it := range items {
+		if it.IsFlag && it.RunCode == "" {
+			continue
+		}
+		id := g.idents[it.Name]
+		g.printf("// Run%s is the handler for %s.\n", id, it.Name)
+		g.printf("func Run%s(opts *Options, cli *boa.CLI) error {\n", id)
+		code := strings.TrimSpace(it.RunCode)
+		for _, imp := range stdImports {
+			if strings.Contains(code, path.Base(imp)+".") {
+				g.imports[imp] = true
+			}
+		}
+		if code == "" {
+			g.printf("// TODO: add RunCode for %s to the schema\n", it.Name)
+		} else {
+			g.printf("%s\n", code)
+		}
+		if !endsInReturn(code) {
+			g.printf("return nil\n")
+		}
+		g.printf("}\n\n")
+	}
+}
+
+// stdImports are added to the generated file when RunCode refers to
+// them; anything else has to be named with -import.
+var stdImports = []string{"errors", "fmt", "io", "log", "os", "os/exec", "strconv", "strings"}
+
+func endsInReturn(code string) bool {
+	lines := strings.Split(code, "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	return last == "return" || strings.HasPrefix(last, "return ")
+}
+
+// goIdent turns an item name such as --dry-run into an exported
+// identifier such as DryRun.
+func goIdent(name string) string {
+	var b strings.Builder
+	up := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			up = true
+			continue
+		}
+		if up {
+			r = unicode.ToUpper(r)
+			up = false
+		}
+		b.WriteRune(r)
+	}
+	id := b.String()
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+func quoteSchema(schema []byte) string {
+	// raw strings cannot hold a back quote and drop carriage returns
+	if bytes.ContainsAny(schema, "`\r") || !utf8.Valid(schema) {
+		return strconv.Quote(string(schema))
+	}
+	return "`" + string(schema) + "`"
+}
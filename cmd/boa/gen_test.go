@@ -0,0 +1,59 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/westarver/boa"
+	"github.com/westarver/boa/boatest"
+)
+
+func genSample(t *testing.T) ([]byte, map[string]boa.CmdLineItem) {
+	t.Helper()
+	schema, err := os.ReadFile("testdata/gen.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := boa.CollectItemsFromJSON(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema, items
+}
+
+func TestGen(t *testing.T) {
+	schema, items := genSample(t)
+	src, err := generate(items, "demo", "gen.json", schema, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	boatest.Golden(t, "gen", string(src))
+
+	// the generated package has to type check against boa, not just parse
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "options.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("demo", fset, []*ast.File{f}, nil); err != nil {
+		t.Errorf("generated code does not type check: %v", err)
+	}
+}
+
+func TestGenBadRunCode(t *testing.T) {
+	schema, items := genSample(t)
+	it := items["deploy"]
+	it.RunCode = "if {"
+	items["deploy"] = it
+	_, err := generate(items, "demo", "gen.json", schema, nil)
+	if err == nil || !strings.Contains(err.Error(), "does not parse") {
+		t.Errorf("err = %v, want generated code does not parse", err)
+	}
+}
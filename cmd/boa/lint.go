@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/westarver/boa"
+)
+
+var errLint = errors.New("lint issues found")
+
+// runLint implements 'boa lint'. It checks the help text and definitions
+// in a schema and fails when it finds anything. Rules can be turned off
+// for the run with -disable or for one item with its NoLint list:
+//
+//	boa lint -disable help-case,optional-no-default schema.json
+func runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the issues as a JSON array")
+	disable := fs.String("disable", "", "comma separated rules not to check")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("lint: expecting exactly one schema file")
+	}
+
+	file := fs.Arg(0)
+	items, err := readSchema(file)
+	if err != nil {
+		return err
+	}
+	var off []string
+	for _, rule := range strings.Split(*disable, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			off = append(off, rule)
+		}
+	}
+
+	issues := boa.Lint(items, off...)
+	if *asJSON {
+		if issues == nil {
+			issues = []boa.Issue{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(issues); err != nil {
+			return err
+		}
+	} else {
+		for _, i := range issues {
+			fmt.Printf("%s: %s\n", file, i)
+		}
+	}
+
+	if len(issues) > 0 {
+		return errLint
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/westarver/boa/boatest"
+)
+
+func TestLintCommand(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want string
+		err  error
+	}{
+		{[]string{"testdata/lint.json"},
+			"testdata/lint.json: --bare: has no ShortHelp [missing-help]\n" +
+				"testdata/lint.json: --n: value is optional but there is no DefaultValue [optional-no-default]\n", errLint},
+		{[]string{"-disable", "missing-help, optional-no-default", "testdata/lint.json"}, "", nil},
+		{[]string{"-disable", " optional-no-default ,", "testdata/lint.json"},
+			"testdata/lint.json: --bare: has no ShortHelp [missing-help]\n", errLint},
+	} {
+		out, err := boatest.Exec(t, "", nil, func() error { return runLint(tc.args) })
+		if err != tc.err {
+			t.Errorf("%q: err = %v, want %v", tc.args, err, tc.err)
+		}
+		if out.Stdout != tc.want {
+			t.Errorf("%q: output\n%s\nwant\n%s", tc.args, out.Stdout, tc.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Command boa is a companion tool for applications that describe their
+// command line with a boa JSON schema.
+//
+//	boa gen [-o file] [-pkg name] schema.json
+//	boa diff [-json] old.json new.json
+//	boa lint [-json] [-disable rules] schema.json
+//	boa man [-o file] schema.json
+//	boa markdown [-o file] schema.json
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: boa <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "    gen       generate a typed Go package from a schema")
+	fmt.Fprintln(os.Stderr, "    diff      list the changes between two schemas, fail on breaking ones")
+	fmt.Fprintln(os.Stderr, "    lint      check the help text and definitions in a schema")
+	fmt.Fprintln(os.Stderr, "    man       write a manual page for the application of a schema")
+	fmt.Fprintln(os.Stderr, "    markdown  write Markdown documentation for the application of a schema")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "gen":
+		err = runGen(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	case "lint":
+		err = runLint(os.Args[2:])
+	case "man":
+		err = runMan(os.Args[2:])
+	case "markdown":
+		err = runMarkdown(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "boa: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "boa:", err)
+		os.Exit(1)
+	}
+}
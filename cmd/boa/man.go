@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/westarver/boa"
+)
+
+// runMan implements 'boa man'. It writes a manual page for the
+// application a schema describes, to stdout or the file given with -o:
+//
+//	boa man -o tool.1 schema.json
+func runMan(args []string) error {
+	fs := flag.NewFlagSet("man", flag.ContinueOnError)
+	out := fs.String("o", "", "write the page to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("man: expecting exactly one schema file")
+	}
+
+	schema, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	p, err := boa.NewParserFromJSON(schema)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fs.Arg(0), err)
+	}
+	page := p.Parse(nil).Man() // nothing is parsed, the errors do not matter
+
+	if *out == "" {
+		_, err = fmt.Print(page)
+		return err
+	}
+	return os.WriteFile(*out, []byte(page), 0644)
+}
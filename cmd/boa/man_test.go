@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/westarver/boa/boatest"
+)
+
+func TestMan(t *testing.T) {
+	out, err := boatest.Exec(t, "", nil, func() error { return runMan([]string{"testdata/man.json"}) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	boatest.Golden(t, "man", out.Stdout)
+
+	file := filepath.Join(t.TempDir(), "tool.1")
+	if _, err := boatest.Exec(t, "", nil, func() error { return runMan([]string{"-o", file, "testdata/man.json"}) }); err != nil {
+		t.Fatal(err)
+	}
+	page, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(page) != out.Stdout {
+		t.Errorf("-o wrote\n%s\nwant\n%s", page, out.Stdout)
+	}
+}
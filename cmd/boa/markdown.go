@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/westarver/boa"
+)
+
+// runMarkdown implements 'boa markdown'. It writes documentation in
+// Markdown for the application a schema describes, to stdout or the file
+// given with -o:
+//
+//	boa markdown -o USAGE.md schema.json
+func runMarkdown(args []string) error {
+	fs := flag.NewFlagSet("markdown", flag.ContinueOnError)
+	out := fs.String("o", "", "write the document to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("markdown: expecting exactly one schema file")
+	}
+
+	schema, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	p, err := boa.NewParserFromJSON(schema)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fs.Arg(0), err)
+	}
+	doc := p.Parse(nil).Markdown() // nothing is parsed, the errors do not matter
+
+	if *out == "" {
+		_, err = fmt.Print(doc)
+		return err
+	}
+	return os.WriteFile(*out, []byte(doc), 0644)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/westarver/boa/boatest"
+)
+
+func TestMarkdown(t *testing.T) {
+	out, err := boatest.Exec(t, "", nil, func() error { return runMarkdown([]string{"testdata/man.json"}) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	boatest.Golden(t, "markdown", out.Stdout)
+
+	for _, want := range []string{
+		"Examples:\n\n```\ntool deploy .staging\n```\n",
+		"## Examples\n\n```\ntool deploy prod -v\n```\n\ndeploy loudly\n",
+	} {
+		if !strings.Contains(out.Stdout, want) {
+			t.Errorf("markdown has no example %q", want)
+		}
+	}
+}
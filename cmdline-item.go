@@ -5,6 +5,7 @@ import (
 	"net/mail"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,32 +14,82 @@ type CLI struct {
 	Items       map[string]CmdLineItem
 	AllHelp     map[string]string
 	Errs        []error
+	Warns       []error // about items that still work but should not be used
+
+	conf    config
+	index   *itemIndex
+	unknown []string
+	passed  []string
+
+	experimental bool // experimental items may be used on this command line
+
+	app     AppInfo
+	builtin string // the built-in asked for, see Builtin
+	topic   string // the command help was asked about
+
+	mu sync.Mutex // guards Errs and Warns for SetError, SetWarning and the reporting funcs
 }
 
 func (C *CLI) Errors() string {
 	// both errors accum in CLI and the errors of each CmdLineItem
 	// are reported with this func
 	var errs []string
+	code := C.errorStyle()
 	for _, c := range C.Items {
-		errs = append(errs, c.Error())
+		for _, e := range c.Errors {
+			errs = append(errs, C.message(e, code))
+		}
 	}
+	C.mu.Lock()
+	defer C.mu.Unlock()
 	for _, e := range C.Errs {
-		errs = append(errs, e.Error())
+		errs = append(errs, C.message(e, code))
 	}
 	return strings.Join(errs, "\n")
 }
 
 func (C *CLI) SetError(err error) {
+	C.mu.Lock()
+	defer C.mu.Unlock()
 	C.Errs = append(C.Errs, err)
 }
 
+func (C *CLI) SetWarning(err error) {
+	C.mu.Lock()
+	defer C.mu.Unlock()
+	C.Warns = append(C.Warns, err)
+}
+
+// Warnings reports what was used that still works but should not be,
+// such as deprecated items. Warnings never count as errors.
+func (C *CLI) Warnings() string {
+	C.mu.Lock()
+	defer C.mu.Unlock()
+	var warns []string
+	code := C.warningStyle()
+	for _, w := range C.Warns {
+		warns = append(warns, C.message(w, code))
+	}
+	return strings.Join(warns, "\n")
+}
+
+func (C *CLI) HasWarnings() bool {
+	C.mu.Lock()
+	defer C.mu.Unlock()
+	return len(C.Warns) != 0
+}
+
 func (C *CLI) HasErrors() bool {
 	return len(C.Errors()) != 0
 }
 
 func (C *CLI) LastError() error {
 	if C.HasErrors() {
-		return C.Errs[len(C.Errs)-1]
+		C.mu.Lock()
+		defer C.mu.Unlock()
+		if len(C.Errs) > 0 {
+			return C.Errs[len(C.Errs)-1]
+		}
 	}
 	return nil
 }
@@ -190,6 +241,44 @@ func (C *CLI) URLSlice(item string) ([]url.URL, bool) {
 	return nil, false
 }
 
+func (C *CLI) StringMap(item string) (map[string]string, bool) {
+	if m, ok := C.Items[item].Value.(map[string]string); ok {
+		return m, ok
+	}
+	return nil, false
+}
+
+func (C *CLI) IntMap(item string) (map[string]int, bool) {
+	if m, ok := C.Items[item].Value.(map[string]int); ok {
+		return m, ok
+	}
+	return nil, false
+}
+
+// Tuple returns the values of an item that takes a fixed number of
+// values, in the order they were given, each of the type of its position.
+func (C *CLI) Tuple(item string) ([]interface{}, bool) {
+	if t, ok := C.Items[item].Value.([]interface{}); ok {
+		return t, ok
+	}
+	return nil, false
+}
+
+// Param returns the value named name of an item that takes a fixed
+// number of values.
+func (C *CLI) Param(item, name string) (interface{}, bool) {
+	t, ok := C.Tuple(item)
+	if !ok {
+		return nil, false
+	}
+	for n, p := range C.Items[item].Params {
+		if p.Name == name && n < len(t) {
+			return t[n], true
+		}
+	}
+	return nil, false
+}
+
 type HelpType int
 
 const (
@@ -206,24 +295,31 @@ func (C *CLI) Help(topic string, ty ...HelpType) string {
 	}
 
 	// deal with alias passed in
-	for _, c := range C.Items {
-		if c.Alias == topic {
-			topic = c.Name
-			break
+	if C.index != nil {
+		topic = C.index.canonical(topic)
+	} else {
+		for _, c := range C.Items {
+			for _, a := range c.AllAliases() {
+				if a == topic {
+					topic = c.Name
+				}
+			}
 		}
 	}
 
 	switch typ {
 	case ShortStr:
 		if i, ok := C.Items[topic]; ok {
-			return i.ShortHelp
+			short, _ := C.conf.helpOf(i)
+			return short
 		}
 	case LongStr:
 		if i, ok := C.Items[topic]; ok {
-			return i.LongHelp
+			_, long := C.conf.helpOf(i)
+			return long
 		}
 	case CombinedStr:
-		return C.AllHelp[topic]
+		return C.itemHelp(topic)
 	}
 
 	return ""
@@ -233,6 +329,7 @@ type CmdLineItem struct {
 	Id           int // use as index to sort the items in as read order
 	Name         string
 	Alias        string
+	Aliases      []string // more aliases, Alias is kept for older schemas
 	ParamType    ParameterType
 	ParamCount   int // -100 means 1 or more are required, -99 is 0 or more
 	ShortHelp    string
@@ -240,6 +337,10 @@ type CmdLineItem struct {
 	Errors       []error
 	Value        interface{} // string values taken from command line may be converted to any type
 	DefaultValue string      // string because all values are taken off the command line as strings
+	Choices      []string    // when not empty the only values accepted, makes the item an enum
+	Params       []ParamSpec // names and types of the values of an item taking ParamCount > 1 values
+	Separator    string      // splits each value of a slice or map item, as in --tags a,b,c
+	OnDuplicate  DupPolicy   // what to do when a key of a map item is given twice
 
 	IsDefault   bool
 	IsFlag      bool
@@ -247,6 +348,27 @@ type CmdLineItem struct {
 	IsParamOpt  bool
 	IsRequired  bool
 	IsDeleted   bool
+	IsSecret    bool // input is masked when the value is prompted for
+
+	IsHidden       bool     // parsed but left out of help and completion
+	IsExperimental bool     // only parsed when experimental items are enabled
+	IsDeprecated   bool     // parsed with a warning
+	ReplacedBy     string   // named in the warning for a deprecated item
+	RemovedIn      string   // version named in the warning for a deprecated item
+	RenamedFrom    []string // old names, read as this item without a word
+
+	Version   string // only used in the BOA-APP-DATA record, printed by --version
+	Commit    string // only used in the BOA-APP-DATA record
+	BuildDate string // only used in the BOA-APP-DATA record
+
+	NoLint   []string  // Lint rules not to check on this item
+	Examples []Example // command lines using the item, shown after its help
+
+	Locales map[string]LocalHelp // help in other languages, by locale such as "de"
+
+	Source Source `json:"-"` // where Value came from, set by the parser
+
+	OnUnknown UnknownPolicy // what to do with unknown args after this command
 
 	RunCode string // the boa-gui tool uses this field for code generation
 	ParName string
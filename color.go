@@ -0,0 +1,133 @@
+package boa
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode says when Usage, Help, Errors and Warnings use ANSI colors.
+type ColorMode int
+
+const (
+	ColorNever  ColorMode = iota // plain text, the same as without WithColor
+	ColorAuto                    // when the output is a terminal and NO_COLOR is not set
+	ColorAlways                  // even when the output is not a terminal
+)
+
+// Style is an ANSI SGR parameter list such as "1" for bold or "1;36" for
+// bold cyan. The empty Style leaves text as it is.
+type Style string
+
+func (s Style) paint(text string) string {
+	if s == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + string(s) + "m" + text + "\x1b[0m"
+}
+
+// Theme gives the style of each part of the output.
+type Theme struct {
+	Heading     Style // the "Usage of" line
+	Name        Style // item names and aliases
+	Placeholder Style // KEY=VALUE and the like after a name
+	Default     Style // default values offered when prompting
+	ErrorCode   Style // the code before an error
+	WarningCode Style // the code before a warning
+}
+
+// DefaultTheme is the theme WithColor uses when WithTheme is not given.
+var DefaultTheme = Theme{
+	Heading:     "1",
+	Name:        "1;36",
+	Placeholder: "33",
+	Default:     "32",
+	ErrorCode:   "1;31",
+	WarningCode: "1;33",
+}
+
+// WithColor turns on colored output in the given mode.
+func WithColor(mode ColorMode) Option {
+	return func(C *CLI) {
+		C.conf.color = mode
+	}
+}
+
+// WithTheme sets the styles colored output uses. It does not turn color
+// on by itself.
+func WithTheme(t Theme) Option {
+	return func(C *CLI) {
+		C.conf.theme = &t
+	}
+}
+
+// styles returns the theme to paint with, nil when output is plain.
+func (cf config) styles() *Theme {
+	switch cf.color {
+	case ColorNever:
+		return nil
+	case ColorAuto:
+		if _, set := os.LookupEnv("NO_COLOR"); set || os.Getenv("TERM") == "dumb" || !isTerminalWriter(cf.out) {
+			return nil
+		}
+	}
+	if cf.theme != nil {
+		return cf.theme
+	}
+	return &DefaultTheme
+}
+
+// isTerminalWriter reports whether w, or stdout when w is nil, is a
+// terminal. Writers that are not files never are.
+func isTerminalWriter(w io.Writer) bool {
+	if w == nil {
+		w = os.Stdout
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// itemHelp is the combined help of the item called name as AllHelp has
+// it, painted when color is on.
+func (C *CLI) itemHelp(name string) string {
+	th := C.conf.styles()
+	if _, shown := C.AllHelp[name]; !shown || th == nil || C.index == nil {
+		return C.AllHelp[name]
+	}
+	it, ok := C.index.cmds[name]
+	if !ok {
+		return C.AllHelp[name]
+	}
+	short, long := C.conf.helpOf(it)
+	return withExamples(formatHelpStyled(it.Name, strings.Join(it.AllAliases(), ", "), it.Placeholder(), short, long, th), it.Examples, th)
+}
+
+// message is Localize with the code painted when color is on.
+func (C *CLI) message(err error, code Style) string {
+	s := C.Localize(err)
+	var pe ParseError
+	if code == "" || !errors.As(err, &pe) {
+		return s
+	}
+	prefix := pe.Code.String() + ":"
+	if !strings.HasPrefix(s, prefix) {
+		return s
+	}
+	return code.paint(pe.Code.String()) + s[len(pe.Code.String()):]
+}
+
+// errorStyle and warningStyle are the styles of codes, "" when plain.
+func (C *CLI) errorStyle() Style {
+	if th := C.conf.styles(); th != nil {
+		return th.ErrorCode
+	}
+	return ""
+}
+
+func (C *CLI) warningStyle() Style {
+	if th := C.conf.styles(); th != nil {
+		return th.WarningCode
+	}
+	return ""
+}
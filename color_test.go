@@ -0,0 +1,101 @@
+package boa
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with testdata/name, or writes it there with -update.
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s:\ngot:\n%q\nwant:\n%q", path, got, want)
+	}
+}
+
+func colorParser(t *testing.T, opts ...Option) *Parser {
+	t.Helper()
+	schema, err := os.ReadFile("testdata/color.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewParserFromJSON(schema, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func allItemHelp(cli *CLI) string {
+	var names []string
+	for name := range cli.AllHelp {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var s string
+	for _, name := range names {
+		s += cli.Help(name)
+	}
+	return s
+}
+
+func TestColorNever(t *testing.T) {
+	args := []string{"--count", "x", "bogus"}
+	plain := colorParser(t).Parse(args)
+	never := colorParser(t, WithColor(ColorNever), WithTheme(DefaultTheme)).Parse(args)
+
+	// help-plain.golden was written by the code before colors existed
+	golden(t, "help-plain.golden", allItemHelp(never))
+	if never.Usage() != plain.Usage() {
+		t.Errorf("usage differs:\n%q\n%q", never.Usage(), plain.Usage())
+	}
+	if never.Errors() != plain.Errors() {
+		t.Errorf("errors differ:\n%q\n%q", never.Errors(), plain.Errors())
+	}
+}
+
+func TestColorThemed(t *testing.T) {
+	theme := Theme{Heading: "4", Name: "35", Placeholder: "2", ErrorCode: "41", WarningCode: "43"}
+	cli := colorParser(t, WithColor(ColorAlways), WithTheme(theme)).Parse([]string{"--count", "x", "bogus"})
+	golden(t, "color-themed.golden", cli.Usage()+"\n"+cli.Help("deploy")+"\n"+cli.Errors())
+}
+
+func TestColorAuto(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	os.Unsetenv("NO_COLOR")
+	file, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	for _, out := range []io.Writer{&bytes.Buffer{}, file} {
+		var C CLI
+		WithColor(ColorAuto)(&C)
+		WithOutput(out)(&C)
+		if C.conf.styles() != nil {
+			t.Errorf("ColorAuto writing to %T colors", out)
+		}
+	}
+	if isTerminalWriter(nil) != isTerminal(os.Stdout) {
+		t.Errorf("a nil writer is not stdout")
+	}
+}
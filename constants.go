@@ -26,4 +26,22 @@ const (
 	TypeEmailSlice
 	TypePhone
 	TypePhoneSlice
+	TypeStringMap
+	TypeIntMap
 )
+
+// IsSlice reports whether items of type p take a list of values.
+func (p ParameterType) IsSlice() bool {
+	switch p {
+	case TypeStringSlice, TypeIntSlice, TypeFloatSlice, TypeTimeSlice,
+		TypeTimeDurationSlice, TypeDateSlice, TypePathSlice, TypeURLSlice,
+		TypeIPv4Slice, TypeEmailSlice, TypePhoneSlice:
+		return true
+	}
+	return false
+}
+
+// IsMap reports whether items of type p take KEY=VALUE pairs.
+func (p ParameterType) IsMap() bool {
+	return p == TypeStringMap || p == TypeIntMap
+}
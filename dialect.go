@@ -0,0 +1,200 @@
+package boa
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dialect selects the command line conventions arguments are read with.
+// Items are always named the same way in the schema, --name for long
+// flags and -n for their aliases; the dialect decides how a user may
+// write them.
+type Dialect int
+
+const (
+	// DialectDefault is what boa has always done: --name=value is split
+	// at the '=' and every single dash argument is a group of one letter
+	// flags, so -abc is -a -b -c.
+	DialectDefault Dialect = iota
+	// DialectPOSIX follows getopt. One letter flags may be grouped and
+	// the first one in a group that takes a value takes the rest of the
+	// group as that value, so -vofile is -v -o file. Arguments starting
+	// with '--' are left as they are.
+	DialectPOSIX
+	// DialectGNU is DialectPOSIX plus long flags, --name=value, and any
+	// unambiguous prefix of a long flag, so --verb is --verbose.
+	DialectGNU
+	// DialectGoFlag follows the flag package: -name and --name are the
+	// same flag, -name=value is allowed and nothing is grouped.
+	DialectGoFlag
+	// DialectWindows reads /name and /name:value (or /name=value). An
+	// argument starting with '/' that does not name an item, such as a
+	// path, is left untouched.
+	DialectWindows
+)
+
+// WithDialect selects the conventions arguments are read with.
+func WithDialect(d Dialect) Option {
+	return func(C *CLI) {
+		C.conf.dialect = d
+	}
+}
+
+// normalize breaks args into tokens according to the dialect of C. It
+// returns the tokens, the index in args each token came from and any
+// errors found on the way.
+func (C *CLI) normalize(ix *itemIndex, args []string) ([]string, []int, []error) {
+	switch C.conf.dialect {
+	case DialectPOSIX, DialectGNU:
+		return splitGetopt(ix, args, C.conf.dialect == DialectGNU)
+	case DialectGoFlag:
+		toks, orig := splitGoFlag(ix, args)
+		return toks, orig, nil
+	case DialectWindows:
+		toks, orig := splitWindows(ix, args)
+		return toks, orig, nil
+	}
+	toks, orig := normalizeArgs(args)
+	return toks, orig, nil
+}
+
+func splitGetopt(ix *itemIndex, args []string, gnu bool) ([]string, []int, []error) {
+	var result []string
+	var orig []int
+	var errs []error
+	add := func(tok string, i int) {
+		result = append(result, tok)
+		orig = append(orig, i)
+	}
+
+	for i, a := range args {
+		switch {
+		case !isFlagToken(a):
+			add(a, i)
+
+		case strings.HasPrefix(a, "--"):
+			if !gnu {
+				add(a, i)
+				continue
+			}
+			name, val, hasVal := strings.Cut(a, "=")
+			full, err := matchLong(ix, name)
+			if err != nil {
+				err.Arg = i
+				errs = append(errs, *err)
+			}
+			add(full, i)
+			if hasVal {
+				add(val, i)
+			}
+
+		default:
+			group := a[1:]
+			for j, r := range group {
+				short := "-" + string(r)
+				add(short, i)
+				rest := group[j+utf8.RuneLen(r):]
+				if it, ok := ix.lookup(short); ok && it.ParamCount != 0 && rest != "" {
+					add(rest, i)
+					break
+				}
+			}
+		}
+	}
+	return result, orig, errs
+}
+
+// matchLong returns the long flag that name is the name of or an
+// unambiguous prefix of. An unknown name is returned as it is and left
+// for the parser to report.
+func matchLong(ix *itemIndex, name string) (string, *ParseError) {
+	if _, ok := ix.lookup(name); ok {
+		return name, nil
+	}
+
+	found := make(map[string]bool)
+	prefix := ix.key(name)
+	for n, item := range ix.names {
+		if strings.HasPrefix(n, "--") && strings.HasPrefix(n, prefix) {
+			found[item] = true
+		}
+	}
+	var names []string
+	for n := range found {
+		names = append(names, n)
+	}
+	switch len(names) {
+	case 0:
+		return name, nil
+	case 1:
+		return names[0], nil
+	}
+	sort.Strings(names)
+	err := Errorf(BeAmbiguousFlag, name, strings.Join(names, ", "))
+	return name, &err
+}
+
+func splitGoFlag(ix *itemIndex, args []string) ([]string, []int) {
+	var result []string
+	var orig []int
+	for i, a := range args {
+		if !isFlagToken(a) {
+			result = append(result, a)
+			orig = append(orig, i)
+			continue
+		}
+
+		name, val, hasVal := strings.Cut(a, "=")
+		base := strings.TrimLeft(name, "-")
+		for _, n := range []string{"--" + base, "-" + base} {
+			if _, ok := ix.lookup(n); ok {
+				name = n
+				break
+			}
+		}
+		result = append(result, name)
+		orig = append(orig, i)
+		if hasVal {
+			result = append(result, val)
+			orig = append(orig, i)
+		}
+	}
+	return result, orig
+}
+
+func splitWindows(ix *itemIndex, args []string) ([]string, []int) {
+	var result []string
+	var orig []int
+	add := func(tok string, i int) {
+		result = append(result, tok)
+		orig = append(orig, i)
+	}
+
+next:
+	for i, a := range args {
+		if len(a) < 2 || a[0] != '/' {
+			add(a, i)
+			continue
+		}
+
+		name, val := a[1:], ""
+		sep := strings.IndexAny(name, ":=")
+		if sep >= 0 {
+			name, val = name[:sep], name[sep+1:]
+		}
+		if !strings.Contains(name, "/") {
+			for _, n := range []string{"--" + name, "-" + name, name} {
+				if _, ok := ix.lookup(n); ok {
+					add(n, i)
+					if sep >= 0 {
+						add(val, i)
+					}
+					continue next
+				}
+			}
+		}
+		add(a, i)
+	}
+	return result, orig
+}
@@ -0,0 +1,83 @@
+package boa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func dialectItems() map[string]CmdLineItem {
+	return map[string]CmdLineItem{
+		"--verbose": {Id: 1, Name: "--verbose", Alias: "-v", IsFlag: true},
+		"--version": {Id: 2, Name: "--version", IsFlag: true},
+		"--output":  {Id: 3, Name: "--output", Alias: "-o", IsFlag: true, ParamType: TypeString, ParamCount: 1},
+		"--name":    {Id: 4, Name: "--name", IsFlag: true, ParamType: TypeString, ParamCount: 1},
+	}
+}
+
+func TestDialects(t *testing.T) {
+	for _, tc := range []struct {
+		dialect Dialect
+		args    []string
+		want    map[string]interface{} // item values, nil for none
+		code    ParseErrCode           // expected error, BeExternalError for none
+	}{
+		// boa's own conventions: every single dash argument is a group of flags
+		{DialectDefault, []string{"-vo", "file"}, map[string]interface{}{"--verbose": true, "--output": "file"}, BeExternalError},
+		{DialectDefault, []string{"--output=file"}, map[string]interface{}{"--output": "file"}, BeExternalError},
+		{DialectDefault, []string{"--verb"}, nil, BeInvalidCommand},
+
+		// getopt: the rest of a group is the value of the flag that takes one
+		{DialectPOSIX, []string{"-vofile"}, map[string]interface{}{"--verbose": true, "--output": "file"}, BeExternalError},
+		{DialectPOSIX, []string{"-ofile"}, map[string]interface{}{"--output": "file"}, BeExternalError},
+		{DialectPOSIX, []string{"--output=file"}, nil, BeInvalidCommand},
+
+		// GNU adds --name=value and unambiguous prefixes
+		{DialectGNU, []string{"-vofile"}, map[string]interface{}{"--verbose": true, "--output": "file"}, BeExternalError},
+		{DialectGNU, []string{"--verb"}, map[string]interface{}{"--verbose": true}, BeExternalError},
+		{DialectGNU, []string{"--out=file"}, map[string]interface{}{"--output": "file"}, BeExternalError},
+		{DialectGNU, []string{"--ver"}, nil, BeAmbiguousFlag},
+
+		// the flag package: one or two dashes, nothing grouped
+		{DialectGoFlag, []string{"-name", "x"}, map[string]interface{}{"--name": "x"}, BeExternalError},
+		{DialectGoFlag, []string{"-name=x", "-v"}, map[string]interface{}{"--name": "x", "--verbose": true}, BeExternalError},
+		{DialectGoFlag, []string{"-vo"}, nil, BeInvalidCommand},
+
+		// Windows: /name:value, and paths are left alone
+		{DialectWindows, []string{"/name:x"}, map[string]interface{}{"--name": "x"}, BeExternalError},
+		{DialectWindows, []string{"/output=x", "/v"}, map[string]interface{}{"--output": "x", "--verbose": true}, BeExternalError},
+		{DialectWindows, []string{"/output", "/tmp/x"}, map[string]interface{}{"--output": "/tmp/x"}, BeExternalError},
+		{DialectWindows, []string{"/tmp/x"}, nil, BeInvalidCommand},
+	} {
+		cli := NewParser(dialectItems(), WithDialect(tc.dialect)).Parse(tc.args)
+
+		var got map[string]interface{}
+		for name, it := range cli.Items {
+			if got == nil {
+				got = make(map[string]interface{})
+			}
+			got[name] = it.Value
+		}
+		if tc.code == BeExternalError {
+			if cli.HasErrors() {
+				t.Errorf("dialect %d %q: errors: %s", tc.dialect, tc.args, cli.Errors())
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("dialect %d %q: values %v, want %v", tc.dialect, tc.args, got, tc.want)
+			}
+			continue
+		}
+		if !hasCode(cli, tc.code) {
+			t.Errorf("dialect %d %q: errors %q, want %s", tc.dialect, tc.args, cli.Errors(), tc.code)
+		}
+	}
+}
+
+// hasCode reports whether cli has an error with code.
+func hasCode(cli *CLI, code ParseErrCode) bool {
+	for _, e := range cli.Errs {
+		if pe, ok := e.(ParseError); ok && pe.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package boa
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ChangeKind tells whether a change between two schemas can break
+// command lines that worked with the old one.
+type ChangeKind int
+
+const (
+	Additive ChangeKind = iota // every old command line still works the same
+	Breaking                   // some old command lines fail or mean something else
+)
+
+func (k ChangeKind) String() string {
+	if k == Breaking {
+		return "breaking"
+	}
+	return "additive"
+}
+
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Change is one difference found by Diff.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	Item string     `json:"item"`
+	What string     `json:"what"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s: %s", c.Kind, c.Item, c.What)
+}
+
+// Diff compares two sets of items, as CollectItemsFromJSON returns them,
+// and lists what changed from old to cur, breaking changes first. Items
+// that were experimental in old promised nothing, so changes to them are
+// never breaking. An item that is gone but listed in RenamedFrom of an
+// item in cur counts as renamed, not removed.
+func Diff(old, cur map[string]CmdLineItem) []Change {
+	var changes []Change
+	add := func(kind ChangeKind, item, format string, args ...any) {
+		changes = append(changes, Change{kind, item, fmt.Sprintf(format, args...)})
+	}
+
+	renamed := make(map[string]string)
+	for _, it := range cur {
+		for _, o := range it.RenamedFrom {
+			renamed[o] = it.Name
+		}
+	}
+
+	for _, name := range diffNames(old) {
+		o := old[name]
+		breaking := Breaking
+		if o.IsExperimental {
+			breaking = Additive
+		}
+		n, ok := cur[name]
+		switch {
+		case !ok && renamed[name] != "":
+			add(Additive, name, "renamed to %s", renamed[name])
+			n = cur[renamed[name]]
+		case !ok:
+			add(breaking, name, "removed")
+			continue
+		case n.IsDeleted && !o.IsDeleted:
+			add(breaking, name, "marked as removed")
+			continue
+		}
+		diffItem(o, n, breaking, add)
+	}
+
+	for _, name := range diffNames(cur) {
+		if _, ok := old[name]; ok || isRenaming(cur[name], old) {
+			continue
+		}
+		n := cur[name]
+		if n.IsRequired && !n.IsExperimental {
+			add(Breaking, name, "added as a required item")
+			continue
+		}
+		add(Additive, name, "added")
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Kind > changes[j].Kind })
+	return changes
+}
+
+// isRenaming reports whether it is an item of old under a new name.
+func isRenaming(it CmdLineItem, old map[string]CmdLineItem) bool {
+	for _, o := range it.RenamedFrom {
+		if _, ok := old[o]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBreaking reports whether any of changes is breaking.
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Kind == Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+func diffItem(o, n CmdLineItem, breaking ChangeKind, add func(ChangeKind, string, string, ...any)) {
+	name := o.Name
+
+	oldAliases, newAliases := o.AllAliases(), n.AllAliases()
+	for _, a := range oldAliases {
+		if !contains(newAliases, a) && !contains(n.RenamedFrom, a) {
+			add(breaking, name, "alias %s removed", a)
+		}
+	}
+	for _, a := range newAliases {
+		if !contains(oldAliases, a) {
+			add(Additive, name, "alias %s added", a)
+		}
+	}
+
+	if o.ParamType != n.ParamType && o.ParamCount != 0 {
+		add(breaking, name, "type changed from %s to %s", typeName(o.ParamType), typeName(n.ParamType))
+	}
+
+	omin, omax := arity(o)
+	nmin, nmax := arity(n)
+	switch {
+	case nmin > omin || nmax < omax:
+		add(breaking, name, "takes %s values, was %s", arityText(nmin, nmax), arityText(omin, omax))
+	case nmin < omin || nmax > omax:
+		add(Additive, name, "takes %s values, was %s", arityText(nmin, nmax), arityText(omin, omax))
+	}
+
+	switch {
+	case len(o.Choices) == 0 && len(n.Choices) > 0:
+		add(breaking, name, "values limited to %s", strings.Join(n.Choices, ", "))
+	case len(o.Choices) > 0 && len(n.Choices) == 0:
+		add(Additive, name, "values no longer limited")
+	default:
+		for _, c := range o.Choices {
+			if !contains(n.Choices, c) {
+				add(breaking, name, "choice %s removed", c)
+			}
+		}
+		for _, c := range n.Choices {
+			if !contains(o.Choices, c) {
+				add(Additive, name, "choice %s added", c)
+			}
+		}
+	}
+
+	switch {
+	case n.IsRequired && !o.IsRequired:
+		add(breaking, name, "now required")
+	case o.IsRequired && !n.IsRequired:
+		add(Additive, name, "no longer required")
+	}
+	if n.IsExclusive && !o.IsExclusive {
+		add(breaking, name, "now exclusive")
+	}
+	if n.IsExperimental && !o.IsExperimental {
+		add(breaking, name, "now experimental")
+	}
+	if o.DefaultValue != n.DefaultValue {
+		add(breaking, name, "default changed from %q to %q", o.DefaultValue, n.DefaultValue)
+	}
+	if o.Separator != n.Separator {
+		add(breaking, name, "separator changed from %q to %q", o.Separator, n.Separator)
+	}
+	if n.OnDuplicate == DupError && o.OnDuplicate != DupError {
+		add(breaking, name, "repeated keys are now an error")
+	}
+	if o.ParName != n.ParName {
+		add(breaking, name, "moved from %q to %q", o.ParName, n.ParName)
+	}
+	for _, c := range o.ChNames {
+		if !contains(n.ChNames, c) {
+			add(breaking, name, "sub command %s removed", c)
+		}
+	}
+	for _, c := range n.ChNames {
+		if !contains(o.ChNames, c) {
+			add(Additive, name, "sub command %s added", c)
+		}
+	}
+	if n.IsDeprecated && !o.IsDeprecated {
+		add(Additive, name, "deprecated%s", deprecationNote(n))
+	}
+}
+
+// diffNames lists the names of items in definition order, leaving out
+// the BOA-APP-DATA record.
+func diffNames(items map[string]CmdLineItem) []string {
+	var names []string
+	for name := range items {
+		if name != AppDataName() {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := items[names[i]], items[names[j]]
+		if a.Id != b.Id {
+			return a.Id < b.Id
+		}
+		return a.Name < b.Name
+	})
+	return names
+}
+
+// arity returns the least and the most values an item takes, max is
+// math.MaxInt when there is no limit.
+func arity(it CmdLineItem) (min, max int) {
+	switch {
+	case it.ParamCount >= 0:
+		return it.ParamCount, it.ParamCount
+	case it.ParamCount == ZeroOrMore:
+		return 0, math.MaxInt
+	case it.ParamCount == OneOrMore:
+		return 1, math.MaxInt
+	}
+	return 0, -it.ParamCount
+}
+
+func arityText(min, max int) string {
+	switch {
+	case max == math.MaxInt:
+		return fmt.Sprintf("%d or more", min)
+	case min == max:
+		return fmt.Sprint(min)
+	}
+	return fmt.Sprintf("%d to %d", min, max)
+}
+
+func typeName(t ParameterType) string {
+	if t.IsSlice() {
+		return TypeToString(t) + " Slice"
+	}
+	return TypeToString(t)
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package boa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	base := func() map[string]CmdLineItem {
+		return map[string]CmdLineItem{
+			"deploy": {Id: 1, Name: "deploy", ParamType: TypeString, ParamCount: 1, ChNames: []string{"--now"}},
+			"--now":  {Id: 2, Name: "--now", IsFlag: true, ParName: "deploy"},
+			"--out":  {Id: 3, Name: "--out", Alias: "-o", IsFlag: true, ParamType: TypeString, ParamCount: 1, DefaultValue: "a.txt"},
+			"--tags": {Id: 4, Name: "--tags", IsFlag: true, ParamType: TypeStringSlice, ParamCount: -3, Choices: []string{"x", "y"}},
+			"--beta": {Id: 5, Name: "--beta", IsFlag: true, IsExperimental: true, ParamType: TypeInt, ParamCount: 1},
+		}
+	}
+	for _, tc := range []struct {
+		name   string
+		change func(m map[string]CmdLineItem)
+		want   []Change
+	}{
+		{"nothing", func(m map[string]CmdLineItem) {}, nil},
+		{"alias removed", func(m map[string]CmdLineItem) {
+			it := m["--out"]
+			it.Alias = ""
+			m["--out"] = it
+		}, []Change{{Breaking, "--out", "alias -o removed"}}},
+		{"alias added", func(m map[string]CmdLineItem) {
+			it := m["--out"]
+			it.Aliases = []string{"-O"}
+			m["--out"] = it
+		}, []Change{{Additive, "--out", "alias -O added"}}},
+		{"alias kept as an old name", func(m map[string]CmdLineItem) {
+			it := m["--out"]
+			it.Alias, it.RenamedFrom = "", []string{"-o"}
+			m["--out"] = it
+		}, nil},
+		{"type changed", func(m map[string]CmdLineItem) {
+			it := m["--out"]
+			it.ParamType = TypePath
+			m["--out"] = it
+		}, []Change{{Breaking, "--out", "type changed from String to File Path"}}},
+		{"arity tightened", func(m map[string]CmdLineItem) {
+			it := m["--tags"]
+			it.ParamCount = -2
+			m["--tags"] = it
+		}, []Change{{Breaking, "--tags", "takes 0 to 2 values, was 0 to 3"}}},
+		{"arity loosened", func(m map[string]CmdLineItem) {
+			it := m["--tags"]
+			it.ParamCount = ZeroOrMore
+			m["--tags"] = it
+		}, []Change{{Additive, "--tags", "takes 0 or more values, was 0 to 3"}}},
+		{"now required", func(m map[string]CmdLineItem) {
+			it := m["--out"]
+			it.IsRequired = true
+			m["--out"] = it
+		}, []Change{{Breaking, "--out", "now required"}}},
+		{"required item added", func(m map[string]CmdLineItem) {
+			m["--key"] = CmdLineItem{Id: 6, Name: "--key", IsFlag: true, IsRequired: true}
+			m["--opt"] = CmdLineItem{Id: 7, Name: "--opt", IsFlag: true}
+		}, []Change{{Breaking, "--key", "added as a required item"}, {Additive, "--opt", "added"}}},
+		{"default changed", func(m map[string]CmdLineItem) {
+			it := m["--out"]
+			it.DefaultValue = "b.txt"
+			m["--out"] = it
+		}, []Change{{Breaking, "--out", `default changed from "a.txt" to "b.txt"`}}},
+		{"choices", func(m map[string]CmdLineItem) {
+			it := m["--tags"]
+			it.Choices = []string{"y", "z"}
+			m["--tags"] = it
+		}, []Change{{Breaking, "--tags", "choice x removed"}, {Additive, "--tags", "choice z added"}}},
+		{"removed", func(m map[string]CmdLineItem) {
+			delete(m, "--out")
+		}, []Change{{Breaking, "--out", "removed"}}},
+		{"marked as removed", func(m map[string]CmdLineItem) {
+			it := m["--out"]
+			it.IsDeleted = true
+			m["--out"] = it
+		}, []Change{{Breaking, "--out", "marked as removed"}}},
+		{"renamed", func(m map[string]CmdLineItem) {
+			it := m["--out"]
+			delete(m, "--out")
+			it.Name, it.RenamedFrom = "--output", []string{"--out"}
+			m["--output"] = it
+		}, []Change{{Additive, "--out", "renamed to --output"}}},
+		{"sub command removed", func(m map[string]CmdLineItem) {
+			it := m["deploy"]
+			it.ChNames = nil
+			m["deploy"] = it
+			delete(m, "--now")
+		}, []Change{{Breaking, "deploy", "sub command --now removed"}, {Breaking, "--now", "removed"}}},
+		{"deprecated", func(m map[string]CmdLineItem) {
+			it := m["--out"]
+			it.IsDeprecated, it.ReplacedBy = true, "--output"
+			m["--out"] = it
+		}, []Change{{Additive, "--out", "deprecated, use --output instead"}}},
+		{"experimental changes promise nothing", func(m map[string]CmdLineItem) {
+			it := m["--beta"]
+			it.ParamType = TypeFloat
+			m["--beta"] = it
+		}, []Change{{Additive, "--beta", "type changed from Integer to Float"}}},
+	} {
+		cur := base()
+		tc.change(cur)
+		got := Diff(base(), cur)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: changes %v, want %v", tc.name, got, tc.want)
+		}
+		if HasBreaking(got) != HasBreaking(tc.want) {
+			t.Errorf("%s: HasBreaking = %t", tc.name, HasBreaking(got))
+		}
+	}
+}
@@ -2,6 +2,8 @@ package boa
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -9,37 +11,33 @@ func AppDataName() string {
 	return "BOA-APP-DATA"
 }
 
-func FromJSON(json []byte, args []string) *CLI {
-	items, err := CollectItemsFromJSON(json)
-	if err != nil {
-		return nil
-	}
-
-	var app string
-
-	// get rid of the app-data record before passing *CLI to caller
+func FromJSON(json []byte, args []string, opts ...Option) *CLI {
+	// the app-data record is left out of the *CLI passed to the caller
 	// if an app needs this record it can be obtained by calling
 	// CollectItemsFromJSON directly
-	if appdata, ok := items[AppDataName()]; ok {
-		app = appdata.Alias //app name is in alias field in that special item
-		delete(items, AppDataName())
-	}
-	cli := ParseCommandLineArgs(items, args)
-	if cli == nil {
+	p, err := NewParserFromJSON(json, opts...)
+	if err != nil {
 		return nil
 	}
-	cli.Application = app
+	return p.Parse(args)
+}
 
-	validateRequirements(items, cli)
-	cli.AllHelp = make(map[string]string)
+// allHelp formats the combined help of every item, keyed by item name,
+// in the language conf selects.
+func allHelp(items map[string]CmdLineItem, conf config) map[string]string {
+	help := make(map[string]string, len(items))
 	for _, item := range items {
-		cli.AllHelp[item.Name] = formatHelp(item.Name, item.Alias, item.ShortHelp, item.LongHelp)
+		if item.IsHidden || item.IsDeleted {
+			continue
+		}
+		short, long := conf.helpOf(item)
+		help[item.Name] = withExamples(formatHelp(item.Name, strings.Join(item.AllAliases(), ", "), item.Placeholder(), short, long), item.Examples, nil)
 	}
-
-	return cli
+	return help
 }
 
 type sliceWrap struct {
+	App      *AppInfo      `json:"app"`
 	Commands []CmdLineItem `json:"commands"`
 }
 
@@ -50,25 +48,60 @@ func CollectItemsFromJSON(jsonBytes []byte) (map[string]CmdLineItem, error) {
 		return nil, err
 	}
 
+	// a second item with the same name would silently replace the first
 	jmap := make(map[string]CmdLineItem)
-	for _, v := range jslice.Commands {
+	first := make(map[string]int)
+	var errs []error
+	for i, v := range jslice.Commands {
+		if j, ok := first[v.Name]; ok {
+			errs = append(errs, Errorf(BeNameCollision, v.Name, fmt.Sprintf("commands[%d]", j), fmt.Sprintf("commands[%d]", i)))
+			continue
+		}
+		first[v.Name] = i
 		jmap[v.Name] = v
 	}
+	if err := CheckNames(jmap, false); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
 	return jmap, nil
 }
 
-func formatHelp(name, alias, short, long string) string {
+func formatHelp(name, alias, placeholder, short, long string) string {
+	return formatHelpStyled(name, alias, placeholder, short, long, nil)
+}
+
+// formatHelpStyled is formatHelp painting the names and placeholder with
+// th. The columns line up as in the plain text.
+func formatHelpStyled(name, alias, placeholder, short, long string, th *Theme) string {
 	name = strings.Trim(name, " \t")
 	s := strings.Trim(short, "\t\n ")
 	s = strings.TrimPrefix(s, name)
 	s = strings.TrimPrefix(s, ":")
 	s = strings.Trim(s, "\t\n ") + "\n"
-	var comb string
+	var comb, painted string
 	if len(alias) > 0 {
 		comb = name + " | " + alias
 	} else {
 		comb = name
 	}
+	painted = comb
+	if th != nil {
+		painted = th.Name.paint(name)
+		if len(alias) > 0 {
+			painted += " | " + th.Name.paint(alias)
+		}
+	}
+	if len(placeholder) > 0 {
+		comb += " " + placeholder
+		if th != nil {
+			painted += " " + th.Placeholder.paint(placeholder)
+		} else {
+			painted = comb
+		}
+	}
 
 	var spc int
 	if len(comb) > 12 {
@@ -77,7 +110,7 @@ func formatHelp(name, alias, short, long string) string {
 		spc = 16 - len(comb)
 	}
 
-	s = comb + strings.Repeat(" ", spc) + s
+	s = painted + strings.Repeat(" ", spc) + s
 	if len(long) == 0 {
 		return s
 	}
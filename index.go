@@ -0,0 +1,105 @@
+package boa
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// AllAliases returns Alias followed by Aliases, without repeats.
+func (c CmdLineItem) AllAliases() []string {
+	var all []string
+	seen := make(map[string]bool)
+	for _, a := range append([]string{c.Alias}, c.Aliases...) {
+		if a != "" && !seen[a] {
+			seen[a] = true
+			all = append(all, a)
+		}
+	}
+	return all
+}
+
+// itemIndex finds items by name or alias without scanning every item.
+type itemIndex struct {
+	cmds       map[string]CmdLineItem
+	names      map[string]string // name or alias, lower cased when ignoreCase, to item name
+	ignoreCase bool
+	help       bool // -h and --help are built in, see isBuiltin
+	version    bool // --version is built in
+}
+
+// newIndex builds the lookup table for cmds. Names and aliases that are
+// used by more than one item are reported; the first item, in definition
+// order, keeps the name.
+func newIndex(cmds map[string]CmdLineItem, ignoreCase bool) (*itemIndex, []error) {
+	ix := &itemIndex{
+		cmds:       cmds,
+		names:      make(map[string]string, 2*len(cmds)),
+		ignoreCase: ignoreCase,
+	}
+
+	items := make([]CmdLineItem, 0, len(cmds))
+	for _, it := range cmds {
+		if it.Name != AppDataName() { // its alias is the app name, not a real alias
+			items = append(items, it)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Id != items[j].Id {
+			return items[i].Id < items[j].Id
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	var errs []error
+	for _, it := range items {
+		names := append([]string{it.Name}, it.AllAliases()...)
+		for _, n := range append(names, it.RenamedFrom...) {
+			k := ix.key(n)
+			if other, ok := ix.names[k]; ok && other != it.Name {
+				errs = append(errs, Errorf(BeNameCollision, n, other, it.Name))
+				continue
+			}
+			ix.names[k] = it.Name
+		}
+	}
+	return ix, errs
+}
+
+// CheckNames reports every name or alias used by more than one item.
+// With ignoreCase names that differ only in case count as the same.
+func CheckNames(cmds map[string]CmdLineItem, ignoreCase bool) error {
+	_, errs := newIndex(cmds, ignoreCase)
+	return errors.Join(errs...)
+}
+
+func (ix *itemIndex) key(s string) string {
+	if ix.ignoreCase {
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// lookup finds the item named or aliased tok.
+func (ix *itemIndex) lookup(tok string) (CmdLineItem, bool) {
+	if name, ok := ix.names[ix.key(tok)]; ok {
+		return ix.cmds[name], true
+	}
+	return CmdLineItem{}, false
+}
+
+// canonical returns the name of the item tok names or aliases, or tok
+// itself when it names nothing.
+func (ix *itemIndex) canonical(tok string) string {
+	if name, ok := ix.names[ix.key(tok)]; ok {
+		return name
+	}
+	return tok
+}
+
+// WithIgnoreCase makes names and aliases match whatever their case.
+func WithIgnoreCase() Option {
+	return func(C *CLI) {
+		C.conf.ignoreCase = true
+	}
+}
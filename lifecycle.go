@@ -0,0 +1,83 @@
+package boa
+
+// An item goes through these states as an app evolves:
+//
+//	IsExperimental  parsed only when experimental items are enabled
+//	IsHidden        parsed but left out of help and completion
+//	IsDeprecated    parsed with a warning in CLI.Warns
+//	IsDeleted       reported as removed instead of as unknown
+//
+// An item that is renamed keeps working under its old names when they
+// are listed in RenamedFrom of the new item.
+
+// WithExperimental enables experimental items on every command line.
+func WithExperimental() Option {
+	return func(C *CLI) {
+		C.conf.experimental = true
+	}
+}
+
+// WithExperimentalFlag enables experimental items on a command line
+// that holds flag, such as --experimental, anywhere before a '--'. The
+// flag itself is not an item and is not kept.
+func WithExperimentalFlag(flag string) Option {
+	return func(C *CLI) {
+		C.conf.experimentalFlag = flag
+	}
+}
+
+func (C *CLI) experimentalEnabled(args []string) bool {
+	if C.conf.experimental {
+		return true
+	}
+	if C.conf.experimentalFlag == "" {
+		return false
+	}
+	for _, a := range args {
+		if a == "--" {
+			break
+		}
+		if a == C.conf.experimentalFlag {
+			return true
+		}
+	}
+	return false
+}
+
+// admit checks the state of an item found at args[arg]. It reports
+// whether the item is to be kept and records the error or warning
+// its state calls for.
+func (C *CLI) admit(cm *CmdLineItem, arg int) bool {
+	var err ParseError
+	switch {
+	case cm.IsDeleted:
+		err = Errorf(BeRemovedItem, cm.Name)
+	case cm.IsExperimental && !C.experimental:
+		how := ""
+		if C.conf.experimentalFlag != "" {
+			how = ", enable it with " + C.conf.experimentalFlag
+		}
+		err = Errorf(BeExperimental, cm.Name, how)
+	case cm.IsDeprecated:
+		w := Errorf(BeDeprecated, cm.Name, deprecationNote(*cm))
+		w.Arg = arg
+		C.SetWarning(w)
+		return true
+	default:
+		return true
+	}
+	err.Arg = arg
+	C.SetError(err)
+	return false
+}
+
+func deprecationNote(it CmdLineItem) string {
+	var note string
+	if it.ReplacedBy != "" {
+		note += ", use " + it.ReplacedBy + " instead"
+	}
+	if it.RemovedIn != "" {
+		note += ", it will be removed in " + it.RemovedIn
+	}
+	return note
+}
@@ -0,0 +1,107 @@
+package boa
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func lifecycleItems() map[string]CmdLineItem {
+	return map[string]CmdLineItem{
+		"--output": {Id: 1, Name: "--output", IsFlag: true, ParamType: TypeString, ParamCount: 1, RenamedFrom: []string{"--out", "-O"},
+			ShortHelp: "--output: where to write"},
+		"--old":    {Id: 2, Name: "--old", IsFlag: true, IsDeprecated: true, ReplacedBy: "--new", RemovedIn: "v2", ShortHelp: "--old: the old way"},
+		"--older":  {Id: 3, Name: "--older", IsFlag: true, IsDeprecated: true},
+		"--gone":   {Id: 4, Name: "--gone", IsFlag: true, IsDeleted: true, ShortHelp: "--gone: was here"},
+		"--beta":   {Id: 5, Name: "--beta", IsFlag: true, IsExperimental: true, ShortHelp: "--beta: try it"},
+		"--secret": {Id: 6, Name: "--secret", IsFlag: true, IsHidden: true, ShortHelp: "--secret: not shown"},
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	for _, tc := range []struct {
+		opts  []Option
+		args  []string
+		items []string // parsed items
+		errs  []string // error messages, in order
+		warns []string // warning messages, in order
+	}{
+		{nil, []string{"--old"}, []string{"--old"}, nil,
+			[]string{"Deprecated: --old is deprecated, use --new instead, it will be removed in v2"}},
+		{nil, []string{"--older"}, []string{"--older"}, nil,
+			[]string{"Deprecated: --older is deprecated"}},
+		{nil, []string{"--gone"}, nil, []string{"RemovedItem: --gone has been removed"}, nil},
+		{nil, []string{"--beta"}, nil, []string{"Experimental: --beta is experimental"}, nil},
+		{[]Option{WithExperimentalFlag("--experimental")}, []string{"--beta"}, nil,
+			[]string{"Experimental: --beta is experimental, enable it with --experimental"}, nil},
+		{[]Option{WithExperimentalFlag("--experimental")}, []string{"--beta", "--experimental"}, []string{"--beta"}, nil, nil},
+		{[]Option{WithExperimentalFlag("--experimental"), WithUnknown(UnknownCollect)}, []string{"--beta", "--", "--experimental"}, nil,
+			[]string{"Experimental: --beta is experimental, enable it with --experimental"}, nil},
+		{[]Option{WithExperimental()}, []string{"--beta"}, []string{"--beta"}, nil, nil},
+		{nil, []string{"--secret"}, []string{"--secret"}, nil, nil},
+
+		// warnings are kept apart from errors
+		{nil, []string{"--old", "--gone"}, []string{"--old"}, []string{"RemovedItem: --gone has been removed"},
+			[]string{"Deprecated: --old is deprecated, use --new instead, it will be removed in v2"}},
+	} {
+		cli := NewParser(lifecycleItems(), tc.opts...).Parse(tc.args)
+
+		var items []string
+		for name := range cli.Items {
+			items = append(items, name)
+		}
+		if !reflect.DeepEqual(items, tc.items) {
+			t.Errorf("%q: items %q, want %q", tc.args, items, tc.items)
+		}
+		if got := messages(cli.Errs); !reflect.DeepEqual(got, tc.errs) {
+			t.Errorf("%q: errors %q, want %q", tc.args, got, tc.errs)
+		}
+		if got := messages(cli.Warns); !reflect.DeepEqual(got, tc.warns) {
+			t.Errorf("%q: warnings %q, want %q", tc.args, got, tc.warns)
+		}
+		if cli.HasWarnings() != (tc.warns != nil) {
+			t.Errorf("%q: HasWarnings = %t", tc.args, cli.HasWarnings())
+		}
+	}
+}
+
+func messages(errs []error) []string {
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	return msgs
+}
+
+func TestRenamedFrom(t *testing.T) {
+	p := NewParser(lifecycleItems())
+	for _, args := range [][]string{{"--out", "x"}, {"-O", "x"}, {"--out=x"}} {
+		cli := p.Parse(args)
+		if s, _ := cli.String("--output"); s != "x" || cli.HasErrors() {
+			t.Errorf("%q: --output = %q, errors %q", args, s, cli.Errors())
+		}
+		// Args writes the new name, so a rendered command line never
+		// depends on the old one still being accepted
+		if got := cli.Args(); !reflect.DeepEqual(got, []string{"--output", "x"}) {
+			t.Errorf("%q: Args = %q, want [--output x]", args, got)
+		}
+	}
+}
+
+func TestHiddenLeftOutOfHelp(t *testing.T) {
+	cli := NewParser(lifecycleItems()).Parse(nil)
+	usage := cli.Usage()
+	for _, name := range []string{"--secret", "--gone"} {
+		if strings.Contains(usage, name) {
+			t.Errorf("usage shows %s:\n%s", name, usage)
+		}
+		if _, ok := cli.AllHelp[name]; ok {
+			t.Errorf("AllHelp has %s", name)
+		}
+	}
+	for _, name := range []string{"--output", "--old", "--beta"} {
+		if !strings.Contains(usage, name) {
+			t.Errorf("usage lacks %s:\n%s", name, usage)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package boa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Lint rules, as named in Issue.Rule and in NoLint.
+const (
+	LintMissingHelp  = "missing-help"        // ShortHelp is empty
+	LintHelpPrefix   = "help-prefix"         // ShortHelp does not start with "name:"
+	LintHelpCase     = "help-case"           // help starts in a case most items do not use
+	LintNameCase     = "name-case"           // a name has capitals while most do not, or the other way
+	LintAliasForm    = "alias-form"          // a flag alias is not -x, or a command alias starts with '-'
+	LintBadDefault   = "bad-default"         // DefaultValue is not a valid value for the item
+	LintNoDefault    = "optional-no-default" // an optional value has no DefaultValue
+	LintExclusiveMix = "exclusive-mix"       // exclusive items are both flags and commands
+)
+
+// Issue is one problem found by Lint.
+type Issue struct {
+	Rule    string `json:"rule"`
+	Item    string `json:"item"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s [%s]", i.Item, i.Message, i.Rule)
+}
+
+// Lint checks the items of a schema, as CollectItemsFromJSON returns
+// them, for help text that is missing or written unlike the rest and for
+// definitions that cannot work as meant. Rules named in disable are not
+// checked, nor are the rules an item lists in its NoLint.
+func Lint(items map[string]CmdLineItem, disable ...string) []Issue {
+	var issues []Issue
+	names := diffNames(items)
+	report := func(rule string, it CmdLineItem, format string, args ...any) {
+		if contains(disable, rule) || contains(it.NoLint, rule) {
+			return
+		}
+		issues = append(issues, Issue{rule, it.Name, fmt.Sprintf(format, args...)})
+	}
+
+	// the case most items use is the one expected of all, removed
+	// items are not checked and do not count
+	helpUpper, nameUpper, withHelp, live := 0, 0, 0, 0
+	for _, name := range names {
+		if items[name].IsDeleted {
+			continue
+		}
+		live++
+		if h := helpText(items[name]); h != "" {
+			withHelp++
+			if startsUpper(h) {
+				helpUpper++
+			}
+		}
+		if hasUpper(name) {
+			nameUpper++
+		}
+	}
+
+	var exclFlags, exclCmds []string
+	for _, name := range names {
+		it := items[name]
+		if it.IsDeleted {
+			continue
+		}
+
+		short := strings.TrimSpace(it.ShortHelp)
+		switch {
+		case short == "":
+			report(LintMissingHelp, it, "has no ShortHelp")
+		case !strings.HasPrefix(short, it.Name+":"):
+			report(LintHelpPrefix, it, "ShortHelp should start with %q", it.Name+":")
+		}
+		if h := helpText(it); h != "" && startsUpper(h) != (2*helpUpper > withHelp) {
+			report(LintHelpCase, it, "help starts with %q unlike most items", firstWord(h))
+		}
+		if hasUpper(name) != (2*nameUpper > live) {
+			report(LintNameCase, it, "name is cased unlike most items")
+		}
+
+		for _, a := range it.AllAliases() {
+			switch {
+			case it.IsFlag && (utf8.RuneCountInString(a) != 2 || a[0] != '-' || a == "--"):
+				report(LintAliasForm, it, "alias %s of a flag should be a dash and one letter", a)
+			case !it.IsFlag && strings.HasPrefix(a, "-"):
+				report(LintAliasForm, it, "alias %s of a command should not start with a dash", a)
+			}
+		}
+
+		if it.DefaultValue != "" {
+			if err := checkDefault(it); err != nil {
+				report(LintBadDefault, it, "default %q does not parse: %v", it.DefaultValue, err)
+			}
+		} else if it.ParamCount < 0 && it.ParamCount > ZeroOrMore && !it.ParamType.IsSlice() && !it.ParamType.IsMap() {
+			report(LintNoDefault, it, "value is optional but there is no DefaultValue")
+		}
+
+		if it.IsExclusive {
+			if it.IsFlag {
+				exclFlags = append(exclFlags, it.Name)
+			} else {
+				exclCmds = append(exclCmds, it.Name)
+			}
+		}
+	}
+
+	if len(exclFlags) > 0 && len(exclCmds) > 0 {
+		for _, name := range append(exclFlags, exclCmds...) {
+			report(LintExclusiveMix, items[name], "exclusive items mix flags (%s) and commands (%s)",
+				strings.Join(exclFlags, ", "), strings.Join(exclCmds, ", "))
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return items[issues[i].Item].Id < items[issues[j].Item].Id })
+	return issues
+}
+
+// checkDefault parses the DefaultValue of it the way a value given on the
+// command line is parsed.
+func checkDefault(it CmdLineItem) error {
+	if it.ParamCount == 0 || it.ParamType == TypeBool {
+		return nil
+	}
+	if it.ParamCount > 1 && !it.ParamType.IsSlice() {
+		return nil // the values of a tuple have no default
+	}
+	def := it.DefaultValue
+	it.DefaultValue = ""
+	it.ChNames = nil
+	it.ParamCount = 1
+	ix, _ := newIndex(map[string]CmdLineItem{it.Name: it}, false)
+	_, _, err := getCmdValues(ix, it.Name, []string{it.Name, def})
+	return err
+}
+
+// helpText is the ShortHelp of it with the name prefix formatHelp strips.
+func helpText(it CmdLineItem) string {
+	s := strings.TrimSpace(it.ShortHelp)
+	s = strings.TrimPrefix(s, it.Name)
+	s = strings.TrimPrefix(s, ":")
+	return strings.TrimSpace(s)
+}
+
+func startsUpper(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsUpper(r)
+}
+
+func hasUpper(s string) bool {
+	return strings.IndexFunc(s, unicode.IsUpper) >= 0
+}
+
+func firstWord(s string) string {
+	if i := strings.IndexAny(s, " \t"); i > 0 {
+		return s[:i]
+	}
+	return s
+}
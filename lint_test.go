@@ -0,0 +1,77 @@
+package boa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func lintBase() map[string]CmdLineItem {
+	return map[string]CmdLineItem{
+		"deploy":    {Id: 1, Name: "deploy", ShortHelp: "deploy: ship it"},
+		"--verbose": {Id: 2, Name: "--verbose", Alias: "-v", IsFlag: true, ShortHelp: "--verbose: say more"},
+		"--out":     {Id: 3, Name: "--out", IsFlag: true, ParamType: TypeString, ParamCount: 1, ShortHelp: "--out: where to write"},
+	}
+}
+
+func TestLintRules(t *testing.T) {
+	for _, tc := range []struct {
+		rule  string
+		items []CmdLineItem // added to lintBase
+		want  []string      // items reported under rule
+	}{
+		{LintMissingHelp, []CmdLineItem{{Id: 9, Name: "--bare", IsFlag: true}}, []string{"--bare"}},
+		{LintHelpPrefix, []CmdLineItem{{Id: 9, Name: "--x", IsFlag: true, ShortHelp: "does x"}}, []string{"--x"}},
+		{LintHelpCase, []CmdLineItem{{Id: 9, Name: "--x", IsFlag: true, ShortHelp: "--x: Does x"}}, []string{"--x"}},
+		{LintNameCase, []CmdLineItem{{Id: 9, Name: "--Loud", IsFlag: true, ShortHelp: "--Loud: shout"}}, []string{"--Loud"}},
+		{LintAliasForm, []CmdLineItem{
+			{Id: 9, Name: "--x", Alias: "-xy", IsFlag: true, ShortHelp: "--x: do x"},
+			{Id: 10, Name: "run", Alias: "-r", ShortHelp: "run: run it"},
+		}, []string{"--x", "run"}},
+		{LintBadDefault, []CmdLineItem{{Id: 9, Name: "--n", IsFlag: true, ParamType: TypeInt, ParamCount: 1, DefaultValue: "x", ShortHelp: "--n: how many"}}, []string{"--n"}},
+		{LintNoDefault, []CmdLineItem{{Id: 9, Name: "--n", IsFlag: true, ParamType: TypeInt, ParamCount: OneOrNone, ShortHelp: "--n: how many"}}, []string{"--n"}},
+		{LintExclusiveMix, []CmdLineItem{
+			{Id: 9, Name: "--x", IsFlag: true, IsExclusive: true, ShortHelp: "--x: do x"},
+			{Id: 10, Name: "run", IsExclusive: true, ShortHelp: "run: run it"},
+		}, []string{"--x", "run"}},
+	} {
+		items := lintBase()
+		for _, it := range tc.items {
+			items[it.Name] = it
+		}
+		var got []string
+		for _, i := range Lint(items) {
+			if i.Rule != tc.rule {
+				t.Errorf("%s: unexpected issue %s", tc.rule, i)
+				continue
+			}
+			got = append(got, i.Item)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: reported %q, want %q", tc.rule, got, tc.want)
+		}
+
+		// and each rule can be turned off for the run or for an item
+		if issues := Lint(items, tc.rule); issues != nil {
+			t.Errorf("%s disabled: %v", tc.rule, issues)
+		}
+		for _, it := range tc.items {
+			it.NoLint = []string{tc.rule}
+			items[it.Name] = it
+		}
+		if issues := Lint(items); issues != nil {
+			t.Errorf("%s in NoLint: %v", tc.rule, issues)
+		}
+	}
+}
+
+func TestLintSkipsDeleted(t *testing.T) {
+	items := lintBase()
+	// removed items are neither reported nor counted when finding the
+	// case most items use
+	for i, name := range []string{"--Old1", "--Old2", "--Old3", "--Old4"} {
+		items[name] = CmdLineItem{Id: 10 + i, Name: name, IsFlag: true, IsDeleted: true, ShortHelp: name + ": Gone"}
+	}
+	if issues := Lint(items); issues != nil {
+		t.Errorf("issues: %v", issues)
+	}
+}
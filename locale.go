@@ -0,0 +1,215 @@
+package boa
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Catalog holds the messages and help of one language. Messages are
+// fmt formats taking the same arguments as the English ones listed with
+// the ParseErrCode constants; a message that is missing falls back to
+// the next locale and in the end to English.
+type Catalog struct {
+	Messages map[ParseErrCode]Message `json:"messages"`
+	Help     map[string]LocalHelp     `json:"help"` // by item name
+	Plural   func(n int) PluralForm   `json:"-"`    // nil picks PluralOne for 1 and PluralOther for the rest
+}
+
+// Message is the text of one error in the forms a language needs for
+// the count it is about, which is its first integer argument. Other is
+// used for any form left empty and for messages without a count.
+type Message struct {
+	Zero  string `json:"zero,omitempty"`
+	One   string `json:"one,omitempty"`
+	Two   string `json:"two,omitempty"`
+	Few   string `json:"few,omitempty"`
+	Many  string `json:"many,omitempty"`
+	Other string `json:"other"`
+}
+
+// PluralForm names the forms of Message.
+type PluralForm int
+
+const (
+	PluralOther PluralForm = iota
+	PluralZero
+	PluralOne
+	PluralTwo
+	PluralFew
+	PluralMany
+)
+
+// LocalHelp is the help of an item in one language. An empty field
+// leaves the help in the schema as it is.
+type LocalHelp struct {
+	ShortHelp string
+	LongHelp  string
+}
+
+// LoadCatalog reads a catalog written as JSON. Messages are keyed by the
+// code names ParseErrCode.String returns:
+//
+//	{"messages": {"InvalidCommand": {"other": "%s: unbekannter Befehl"}},
+//	 "help": {"--verbose": {"ShortHelp": "mehr ausgeben"}}}
+func LoadCatalog(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// WithLocale selects the language of messages and help. tag is a locale
+// such as "de" or "pt_BR.UTF-8"; when it is empty the locale is taken
+// from LC_ALL, LC_MESSAGES or LANG. A regional locale falls back to its
+// language, pt_BR to pt, and then to the English built in.
+func WithLocale(tag string) Option {
+	return func(C *CLI) {
+		t := tag
+		if t == "" {
+			t = envLocale() // read each time the option is used
+		}
+		C.conf.locales = localeChain(t)
+	}
+}
+
+// WithCatalog adds the catalog for the locale tag.
+func WithCatalog(tag string, c *Catalog) Option {
+	return func(C *CLI) {
+		catalogs := make(map[string]*Catalog, len(C.conf.catalogs)+1)
+		for t, c := range C.conf.catalogs {
+			catalogs[t] = c
+		}
+		catalogs[normLocale(tag)] = c
+		C.conf.catalogs = catalogs
+	}
+}
+
+func envLocale() string {
+	for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if tag := os.Getenv(v); tag != "" {
+			return tag
+		}
+	}
+	return ""
+}
+
+// normLocale turns pt-BR.UTF-8@euro into pt_BR.
+func normLocale(tag string) string {
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ReplaceAll(tag, "-", "_")
+}
+
+// localeChain lists the locales to look in for tag, most specific first.
+func localeChain(tag string) []string {
+	tag = normLocale(tag)
+	if tag == "" || tag == "C" || tag == "POSIX" {
+		return nil
+	}
+	chain := []string{tag}
+	if lang, _, ok := strings.Cut(tag, "_"); ok {
+		chain = append(chain, lang)
+	}
+	return chain
+}
+
+// message returns the format for code in the first locale that has it.
+func (cf config) message(code ParseErrCode, args []any) (string, bool) {
+	for _, tag := range cf.locales {
+		c := cf.catalogs[tag]
+		if c == nil {
+			continue
+		}
+		if m, ok := c.Messages[code]; ok {
+			return m.form(c.pluralForm(args)), true
+		}
+	}
+	return "", false
+}
+
+func (c *Catalog) pluralForm(args []any) PluralForm {
+	for _, a := range args {
+		if n, ok := a.(int); ok {
+			if c.Plural != nil {
+				return c.Plural(n)
+			}
+			if n == 1 {
+				return PluralOne
+			}
+			return PluralOther
+		}
+	}
+	return PluralOther
+}
+
+func (m Message) form(f PluralForm) string {
+	var s string
+	switch f {
+	case PluralZero:
+		s = m.Zero
+	case PluralOne:
+		s = m.One
+	case PluralTwo:
+		s = m.Two
+	case PluralFew:
+		s = m.Few
+	case PluralMany:
+		s = m.Many
+	}
+	if s == "" {
+		return m.Other
+	}
+	return s
+}
+
+// helpOf returns the short and long help of it in the first locale that
+// has them, looking in the item itself before the catalogs.
+func (cf config) helpOf(it CmdLineItem) (string, string) {
+	short, long := "", ""
+	for _, tag := range cf.locales {
+		for t, h := range it.Locales {
+			if normLocale(t) == tag {
+				short, long = first(short, h.ShortHelp), first(long, h.LongHelp)
+			}
+		}
+		if c := cf.catalogs[tag]; c != nil {
+			h := c.Help[it.Name]
+			short, long = first(short, h.ShortHelp), first(long, h.LongHelp)
+		}
+	}
+	return first(short, it.ShortHelp), first(long, it.LongHelp)
+}
+
+func first(s ...string) string {
+	for _, e := range s {
+		if e != "" {
+			return e
+		}
+	}
+	return ""
+}
+
+// Localize returns the message of err in the language chosen with
+// WithLocale. Errors that are not a ParseError, and messages no catalog
+// has, are given as err.Error() gives them.
+func (C *CLI) Localize(err error) string {
+	pe, ok := err.(ParseError)
+	if !ok {
+		if p, isPtr := err.(*ParseError); isPtr && p != nil {
+			pe, ok = *p, true
+		}
+	}
+	if !ok {
+		return err.Error()
+	}
+	args := pe.fmtArgs()
+	format, ok := C.conf.message(pe.Code, args)
+	if !ok {
+		return pe.Error()
+	}
+	return fmt.Sprintf("%s: %s", pe.Code, fmt.Sprintf(format, args...))
+}
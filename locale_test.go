@@ -0,0 +1,155 @@
+package boa
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocaleChain(t *testing.T) {
+	for _, tc := range []struct {
+		tag  string
+		want []string
+	}{
+		{"de", []string{"de"}},
+		{"pt-BR.UTF-8@euro", []string{"pt_BR", "pt"}},
+		{"en_US.UTF-8", []string{"en_US", "en"}},
+		{"C", nil},
+		{"POSIX", nil},
+		{"", nil},
+	} {
+		if got := localeChain(tc.tag); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("localeChain(%q) = %q, want %q", tc.tag, got, tc.want)
+		}
+	}
+}
+
+func localeCatalogs() []Option {
+	de, err := LoadCatalog([]byte(`{
+		"messages": {
+			"InvalidCommand": {"other": "%s: unbekannter Befehl oder Schalter"},
+			"MissingParam": {"one": "%s: Wert %d (%s) fehlt", "other": "%s: Werte bis %d (%s) fehlen"}
+		},
+		"help": {"--verbose": {"ShortHelp": "--verbose: mehr ausgeben"}, "--out": {"ShortHelp": "--out: Ziel"}}
+	}`))
+	if err != nil {
+		panic(err)
+	}
+	ptBR := &Catalog{Messages: map[ParseErrCode]Message{BeNotAnInt: {Other: "%s não é um número inteiro (%s)"}}}
+	return []Option{WithCatalog("de", de), WithCatalog("pt-BR", ptBR)}
+}
+
+func localeItems() map[string]CmdLineItem {
+	return map[string]CmdLineItem{
+		"--verbose": {Id: 1, Name: "--verbose", IsFlag: true, ShortHelp: "--verbose: say more",
+			Locales: map[string]LocalHelp{"de_AT": {ShortHelp: "--verbose: mehr sagen"}}},
+		"--out":   {Id: 2, Name: "--out", IsFlag: true, ParamType: TypeString, ParamCount: 1, ShortHelp: "--out: where to write"},
+		"--count": {Id: 3, Name: "--count", IsFlag: true, ParamType: TypeInt, ParamCount: 1, ShortHelp: "--count: how many"},
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	for _, tc := range []struct {
+		locale string
+		err    ParseError
+		want   string
+	}{
+		{"de_DE.UTF-8", Errorf(BeInvalidCommand, "x"), "InvalidCommand: x: unbekannter Befehl oder Schalter"},
+		{"de", Errorf(BeMissingParam, "--size", 1, "width"), "MissingParam: --size: Wert 1 (width) fehlt"},
+		{"de", Errorf(BeMissingParam, "--size", 2, "height"), "MissingParam: --size: Werte bis 2 (height) fehlen"},
+		{"pt_BR", Errorf(BeNotAnInt, "x", "--count"), "NotAnInt: x não é um número inteiro (--count)"},
+
+		// no catalog has it, or no catalog for the locale: English
+		{"pt_BR", Errorf(BeInvalidCommand, "x"), Errorf(BeInvalidCommand, "x").Error()},
+		{"fr", Errorf(BeInvalidCommand, "x"), Errorf(BeInvalidCommand, "x").Error()},
+		{"C", Errorf(BeInvalidCommand, "x"), Errorf(BeInvalidCommand, "x").Error()},
+	} {
+		opts := append(localeCatalogs(), WithLocale(tc.locale))
+		cli := NewParser(localeItems(), opts...).Parse(nil)
+		if got := cli.Localize(tc.err); got != tc.want {
+			t.Errorf("%s: %q, want %q", tc.locale, got, tc.want)
+		}
+		if got := cli.Localize(&tc.err); got != tc.want {
+			t.Errorf("%s through a pointer: %q, want %q", tc.locale, got, tc.want)
+		}
+	}
+
+	cli := NewParser(localeItems(), localeCatalogs()...).Parse(nil)
+	if got := cli.Localize(errors.New("plain")); got != "plain" {
+		t.Errorf("plain error: %q", got)
+	}
+}
+
+func TestPluralRule(t *testing.T) {
+	c := &Catalog{
+		Messages: map[ParseErrCode]Message{BeMissingParam: {Zero: "%[1]s zero", Few: "%[1]s few %[2]d", Other: "%[1]s other"}},
+		Plural: func(n int) PluralForm {
+			if n == 0 {
+				return PluralZero
+			}
+			if n < 5 {
+				return PluralFew
+			}
+			return PluralMany // empty, so Other is used
+		},
+	}
+	cli := NewParser(localeItems(), WithCatalog("pl", c), WithLocale("pl")).Parse(nil)
+	for n, want := range map[int]string{0: "--x zero", 3: "--x few 3", 7: "--x other"} {
+		if got := cli.Localize(Errorf(BeMissingParam, "--x", n, "v")); got != "MissingParam: "+want {
+			t.Errorf("%d: %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	opts := append(localeCatalogs(), WithLocale(""))
+	cli := NewParser(localeItems(), opts...).Parse([]string{"x"})
+	if got := cli.Localize(cli.LastError()); !strings.Contains(got, "unbekannter Befehl") {
+		t.Errorf("LANG=de_DE: %q", got)
+	}
+
+	t.Setenv("LC_ALL", "C")
+	cli = NewParser(localeItems(), opts...).Parse([]string{"x"})
+	if got := cli.Localize(cli.LastError()); got != cli.LastError().Error() {
+		t.Errorf("LC_ALL=C overrides LANG: %q", got)
+	}
+}
+
+func TestLocalHelp(t *testing.T) {
+	for _, tc := range []struct {
+		locale  string
+		verbose string
+		out     string
+	}{
+		{"de_AT", "mehr sagen", "Ziel"},      // the item's own help wins over the catalog
+		{"de_DE", "mehr ausgeben", "Ziel"},   // the catalog of the language
+		{"fr", "say more", "where to write"}, // the schema
+	} {
+		opts := append(localeCatalogs(), WithLocale(tc.locale))
+		cli := NewParser(localeItems(), opts...).Parse(nil)
+		if h := cli.Help("--verbose"); !strings.Contains(h, tc.verbose) {
+			t.Errorf("%s: --verbose help %q, want %q", tc.locale, h, tc.verbose)
+		}
+		if h := cli.Help("--out"); !strings.Contains(h, tc.out) {
+			t.Errorf("%s: --out help %q, want %q", tc.locale, h, tc.out)
+		}
+	}
+}
+
+func TestParseErrorComparable(t *testing.T) {
+	err := Errorf(BeNotAnInt, "x", "--count")
+	var target error = err
+	if target != error(err) {
+		t.Errorf("a ParseError is not equal to itself")
+	}
+	if !errors.Is(target, err) {
+		t.Errorf("errors.Is does not find the same ParseError")
+	}
+	if Errorf(BeNotAnInt, "x", "--count") == err {
+		t.Errorf("two separately made errors compare equal")
+	}
+}
@@ -0,0 +1,137 @@
+package boa
+
+import "strings"
+
+// DupPolicy says what happens when a key of a map item is given twice.
+type DupPolicy int
+
+const (
+	DupLast  DupPolicy = iota // the last value given for a key is kept
+	DupFirst                  // the first value given for a key is kept
+	DupError                  // a key given twice is an error
+)
+
+// Placeholder returns the text shown after the name of an item in help
+// for the kinds of values that need explaining, such as KEY=VALUE for a
+// map item. It is empty for everything else.
+func (c CmdLineItem) Placeholder() string {
+	var ph string
+	switch {
+	case c.ParamType == TypeStringMap:
+		ph = "KEY=VALUE"
+	case c.ParamType == TypeIntMap:
+		ph = "KEY=INT"
+	case c.ParamType.IsSlice() && c.Separator != "":
+		ph = "VALUE"
+	default:
+		return ""
+	}
+	if c.Separator != "" {
+		ph += "[" + c.Separator + ph + "...]"
+	}
+	return ph
+}
+
+// splitEscaped splits s at every sep that is not preceded by a
+// backslash. A backslash before sep or before another backslash is
+// dropped, any other backslash is kept.
+func splitEscaped(s, sep string) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && strings.HasPrefix(s[i+1:], sep):
+			part.WriteString(sep)
+			i += len(sep)
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '\\':
+			part.WriteByte('\\')
+			i++
+		case strings.HasPrefix(s[i:], sep):
+			parts = append(parts, part.String())
+			part.Reset()
+			i += len(sep) - 1
+		default:
+			part.WriteByte(s[i])
+		}
+	}
+	return append(parts, part.String())
+}
+
+// parseMap builds the value of a map item from KEY=VALUE pairs, using
+// conv to turn each VALUE into the element type of the map.
+func parseMap(pairs []string, cmd *CmdLineItem, conv func(string) (interface{}, error)) (interface{}, error) {
+	m := newMap(cmd.ParamType)
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return m, Errorf(BeNotAKeyValue, p, cmd.Name)
+		}
+		val, err := conv(v)
+		if err != nil {
+			return m, err
+		}
+		if err := setKey(m, k, val, cmd); err != nil {
+			return m, err
+		}
+	}
+	return m, nil
+}
+
+// mergeMap adds the pairs already found for a map item given more than
+// once to the value just parsed in cm, as if all came in one list.
+func mergeMap(prev, cm *CmdLineItem) error {
+	merged := newMap(cm.ParamType)
+	var err error
+	for _, src := range []interface{}{prev.Value, cm.Value} {
+		switch m := src.(type) {
+		case map[string]string:
+			for k, v := range m {
+				if e := setKey(merged, k, v, cm); e != nil && err == nil {
+					err = e
+				}
+			}
+		case map[string]int:
+			for k, v := range m {
+				if e := setKey(merged, k, v, cm); e != nil && err == nil {
+					err = e
+				}
+			}
+		}
+	}
+	cm.Value = merged
+	return err
+}
+
+func newMap(t ParameterType) interface{} {
+	if t == TypeIntMap {
+		return map[string]int{}
+	}
+	return map[string]string{}
+}
+
+func setKey(m interface{}, k string, v interface{}, cmd *CmdLineItem) error {
+	var exists bool
+	switch mm := m.(type) {
+	case map[string]string:
+		_, exists = mm[k]
+	case map[string]int:
+		_, exists = mm[k]
+	}
+
+	if exists {
+		switch cmd.OnDuplicate {
+		case DupFirst:
+			return nil
+		case DupError:
+			return Errorf(BeDuplicateKey, k, cmd.Name)
+		}
+	}
+
+	switch mm := m.(type) {
+	case map[string]string:
+		mm[k] = v.(string)
+	case map[string]int:
+		mm[k] = v.(int)
+	}
+	return nil
+}
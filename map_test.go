@@ -0,0 +1,99 @@
+package boa
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitEscaped(t *testing.T) {
+	for _, tc := range []struct {
+		s, sep string
+		want   []string
+	}{
+		{"a,b,c", ",", []string{"a", "b", "c"}},
+		{`a\,b,c`, ",", []string{"a,b", "c"}},
+		{`a\\,b`, ",", []string{`a\`, "b"}},
+		{`a\b,c`, ",", []string{`a\b`, "c"}},
+		{"a,,b,", ",", []string{"a", "", "b", ""}},
+		{"a::b:c", "::", []string{"a", "b:c"}},
+		{`a\::b`, "::", []string{"a::b"}},
+		{`a\`, ",", []string{`a\`}},
+		{"", ",", []string{""}},
+	} {
+		if got := splitEscaped(tc.s, tc.sep); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("splitEscaped(%q, %q) = %q, want %q", tc.s, tc.sep, got, tc.want)
+		}
+	}
+}
+
+func TestMaps(t *testing.T) {
+	item := func(typ ParameterType, dup DupPolicy) map[string]CmdLineItem {
+		return map[string]CmdLineItem{
+			"--env": {Id: 1, Name: "--env", IsFlag: true, ParamType: typ, ParamCount: OneOrMore, Separator: ",", OnDuplicate: dup},
+		}
+	}
+	for _, tc := range []struct {
+		typ  ParameterType
+		dup  DupPolicy
+		args []string
+		want interface{}
+		code ParseErrCode // BeExternalError for none
+	}{
+		{TypeStringMap, DupLast, []string{"--env", "a=1", `b=x\,y,c=`}, map[string]string{"a": "1", "b": "x,y", "c": ""}, BeExternalError},
+		{TypeStringMap, DupLast, []string{"--env", "a=1=2"}, map[string]string{"a": "1=2"}, BeExternalError},
+		{TypeStringMap, DupLast, []string{"--env", "a=1,a=2"}, map[string]string{"a": "2"}, BeExternalError},
+		{TypeStringMap, DupFirst, []string{"--env", "a=1,a=2"}, map[string]string{"a": "1"}, BeExternalError},
+		{TypeStringMap, DupError, []string{"--env", "a=1,a=2"}, nil, BeDuplicateKey},
+
+		// the same rules when the item is given more than once
+		{TypeStringMap, DupLast, []string{"--env", "a=1", "--env", "a=2", "b=3"}, map[string]string{"a": "2", "b": "3"}, BeExternalError},
+		{TypeStringMap, DupFirst, []string{"--env", "a=1", "--env", "a=2"}, map[string]string{"a": "1"}, BeExternalError},
+		{TypeStringMap, DupError, []string{"--env", "a=1", "--env", "a=2"}, nil, BeDuplicateKey},
+
+		{TypeStringMap, DupLast, []string{"--env", "a"}, nil, BeNotAKeyValue},
+		{TypeStringMap, DupLast, []string{"--env", "=1"}, nil, BeNotAKeyValue},
+		{TypeIntMap, DupLast, []string{"--env", "a=1,b=-2"}, map[string]int{"a": 1, "b": -2}, BeExternalError},
+		{TypeIntMap, DupLast, []string{"--env", "a=x"}, nil, BeNotAnInt},
+		{TypeIntMap, DupLast, []string{"--env", "a="}, nil, BeNotAnInt},
+	} {
+		cli := NewParser(item(tc.typ, tc.dup)).Parse(tc.args)
+		if tc.code != BeExternalError {
+			if !hasCode(cli, tc.code) {
+				t.Errorf("%q: errors %q, want %s", tc.args, cli.Errors(), tc.code)
+			}
+			continue
+		}
+		if cli.HasErrors() {
+			t.Errorf("%q: errors: %s", tc.args, cli.Errors())
+		}
+		if got := cli.Items["--env"].Value; !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q: --env = %#v, want %#v", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	for _, tc := range []struct {
+		it   CmdLineItem
+		want string
+	}{
+		{CmdLineItem{ParamType: TypeStringMap}, "KEY=VALUE"},
+		{CmdLineItem{ParamType: TypeStringMap, Separator: ","}, "KEY=VALUE[,KEY=VALUE...]"},
+		{CmdLineItem{ParamType: TypeIntMap}, "KEY=INT"},
+		{CmdLineItem{ParamType: TypeStringSlice, Separator: ":"}, "VALUE[:VALUE...]"},
+		{CmdLineItem{ParamType: TypeStringSlice}, ""},
+		{CmdLineItem{ParamType: TypeString, Separator: ","}, ""},
+	} {
+		if got := tc.it.Placeholder(); got != tc.want {
+			t.Errorf("%v with %q: placeholder %q, want %q", TypeToString(tc.it.ParamType), tc.it.Separator, got, tc.want)
+		}
+	}
+
+	items := map[string]CmdLineItem{
+		"--env": {Id: 1, Name: "--env", IsFlag: true, ParamType: TypeStringMap, ParamCount: OneOrMore, ShortHelp: "--env: variables"},
+	}
+	if h := NewParser(items).Parse(nil).Help("--env"); !strings.Contains(h, "--env KEY=VALUE") {
+		t.Errorf("help %q lacks the placeholder", h)
+	}
+}
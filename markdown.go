@@ -0,0 +1,90 @@
+package boa
+
+import "strings"
+
+// Markdown returns documentation for the application in Markdown: the
+// AppInfo, the help of every item with its examples and the examples of
+// the application.
+func (C *CLI) Markdown() string {
+	name := first(C.app.Name, C.Application, "app")
+
+	var b strings.Builder
+	b.WriteString("# " + name + "\n\n")
+	if C.app.Description != "" {
+		b.WriteString(strings.TrimSpace(C.app.Description) + "\n\n")
+	}
+	if C.app.Version != "" {
+		b.WriteString("Version " + C.app.Version + "\n\n")
+	}
+	b.WriteString("## Usage\n\n```\n" + name + " [OPTIONS]\n```\n\n")
+
+	if C.index != nil {
+		if names := C.helpOrder(); len(names) > 0 {
+			b.WriteString("## Options\n\n")
+			for _, n := range names {
+				it, ok := C.index.cmds[n]
+				if !ok {
+					continue
+				}
+				b.WriteString(markdownItem(it, C.conf))
+			}
+		}
+	}
+
+	if len(C.app.Examples) > 0 {
+		b.WriteString("## Examples\n\n" + markdownExamples(C.app.Examples))
+	}
+	if C.app.Epilog != "" {
+		b.WriteString(strings.TrimSpace(C.app.Epilog) + "\n\n")
+	}
+	if len(C.app.Authors) > 0 {
+		b.WriteString("## Authors\n\n")
+		for _, a := range C.app.Authors {
+			b.WriteString("- " + a + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if C.app.License != "" {
+		b.WriteString("## License\n\n" + C.app.License + "\n\n")
+	}
+	if C.app.Homepage != "" {
+		b.WriteString("## Homepage\n\n<" + C.app.Homepage + ">\n\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func markdownItem(it CmdLineItem, conf config) string {
+	var b strings.Builder
+	names := append([]string{it.Name}, it.AllAliases()...)
+	for i, n := range names {
+		names[i] = "`" + n + "`"
+	}
+	head := strings.Join(names, ", ")
+	if ph := it.Placeholder(); ph != "" {
+		head += " `" + ph + "`"
+	}
+	b.WriteString("### " + head + "\n\n")
+
+	short, long := conf.helpOf(it)
+	if s := helpText(CmdLineItem{Name: it.Name, ShortHelp: short}); s != "" {
+		b.WriteString(s + "\n\n")
+	}
+	if long = strings.TrimSpace(long); long != "" {
+		b.WriteString(long + "\n\n")
+	}
+	if len(it.Examples) > 0 {
+		b.WriteString("Examples:\n\n" + markdownExamples(it.Examples))
+	}
+	return b.String()
+}
+
+func markdownExamples(examples []Example) string {
+	var b strings.Builder
+	for _, e := range examples {
+		b.WriteString("```\n" + e.Line + "\n```\n\n")
+		if e.Description != "" {
+			b.WriteString(e.Description + "\n\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package boa
+
+import "io"
+
+// Option configures how a command line is parsed. Options are passed
+// to FromJSON or ParseCommandLineArgs and are applied to the CLI before
+// any argument is looked at.
+type Option func(*CLI)
+
+// config holds what the options set. A Parser keeps one and every CLI
+// it returns gets a copy.
+type config struct {
+	prompter      *Prompter
+	responseFiles bool
+	getenv        func(string) string
+	dialect       Dialect
+	onUnknown     UnknownPolicy
+	passthrough   bool
+	noIntersperse bool
+	ignoreCase    bool
+
+	experimental     bool   // experimental items are always enabled
+	experimentalFlag string // or enabled by this argument
+
+	locales  []string // most specific first, none for English
+	catalogs map[string]*Catalog
+
+	color ColorMode
+	theme *Theme
+
+	help    bool      // -h, --help and the help command are built in
+	version bool      // --version is built in
+	out     io.Writer // where the built-ins print
+
+	app *AppInfo // in place of the one in the schema
+}
+
+// WithPrompter lets validateRequirements ask for required items that
+// are missing from the command line instead of reporting them as errors.
+func WithPrompter(p *Prompter) Option {
+	return func(C *CLI) {
+		C.conf.prompter = p
+	}
+}
+
+// WithResponseFiles expands arguments of the form @file into the
+// arguments written in file before anything else is done with them.
+func WithResponseFiles() Option {
+	return func(C *CLI) {
+		C.conf.responseFiles = true
+	}
+}
+
+// WithEnv makes ParseString replace $NAME and ${NAME} outside of single
+// quotes with getenv(NAME). Pass os.Getenv to use the environment.
+func WithEnv(getenv func(string) string) Option {
+	return func(C *CLI) {
+		C.conf.getenv = getenv
+	}
+}
@@ -3,8 +3,14 @@ package boa
 import "fmt"
 
 type ParseError struct {
-	Code ParseErrCode
-	Err  error
+	Code   ParseErrCode
+	Err    error
+	Arg    int // index of the argument the error was found at, -1 if unknown
+	Offset int // byte offset of that argument in the string given to ParseString, -1 if unknown
+
+	// what Err was formatted with, for Localize. It is behind a pointer
+	// so that ParseError values can still be compared with ==.
+	args *[]any
 }
 
 type ParseErrCode int
@@ -13,6 +19,22 @@ func (c ParseErrCode) String() string {
 	return codestr(c)
 }
 
+// MarshalText gives the code by the name String returns, the way codes
+// are keyed in the messages of a Catalog.
+func (c ParseErrCode) MarshalText() ([]byte, error) {
+	return []byte(codestr(c)), nil
+}
+
+func (c *ParseErrCode) UnmarshalText(text []byte) error {
+	for code := BeExternalError; code <= BeDeprecated; code++ {
+		if codestr(code) == string(text) {
+			*c = code
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown error code %s", text)
+}
+
 const (
 	//errors from reading input script
 	BeExternalError ParseErrCode = iota
@@ -85,6 +107,26 @@ const (
 	BeNotAURL
 	//"%s, argument for %s, cannot be interpreted as an IP address of IPv4 format"
 	BeNotAnIPv4
+	//"%s, argument for %s, is not one of %s"
+	BeNotAChoice
+	//"unterminated %s quote in %s"
+	BeUnterminatedQuote
+	//"%s is ambiguous, it could be any of %s"
+	BeAmbiguousFlag
+	//"%s is missing value %d (%s)"
+	BeMissingParam
+	//"%s, argument for %s, is not of the form KEY=VALUE"
+	BeNotAKeyValue
+	//"key %s of %s was given more than once"
+	BeDuplicateKey
+	//"%s is used by both %s and %s"
+	BeNameCollision
+	//"%s has been removed"
+	BeRemovedItem
+	//"%s is experimental%s"
+	BeExperimental
+	//"%s is deprecated%s" (a warning)
+	BeDeprecated
 )
 
 func (c ParseErrCode) fmts() string {
@@ -97,9 +139,20 @@ func (e ParseError) Error() string {
 
 func newParseError(code ParseErrCode, fmtstr string, args ...any) ParseError {
 	return ParseError{
-		Code: code,
-		Err:  fmt.Errorf(fmtstr, args...),
+		Code:   code,
+		Err:    fmt.Errorf(fmtstr, args...),
+		Arg:    -1,
+		Offset: -1,
+		args:   &args,
+	}
+}
+
+// fmtArgs returns what Err was formatted with.
+func (e ParseError) fmtArgs() []any {
+	if e.args == nil {
+		return nil
 	}
+	return *e.args
 }
 
 func Errorf(code ParseErrCode, args ...any) ParseError {
@@ -174,6 +227,26 @@ func stringFromCode(code ParseErrCode) string {
 		return "%s, argument for %s, cannot be interpreted as a URL"
 	case BeNotAnIPv4:
 		return "%s, argument for %s, cannot be interpreted as an IP address of IPv4 format"
+	case BeNotAChoice:
+		return "%s, argument for %s, is not one of %s"
+	case BeUnterminatedQuote:
+		return "unterminated %s quote in %s"
+	case BeAmbiguousFlag:
+		return "%s is ambiguous, it could be any of %s"
+	case BeMissingParam:
+		return "%s is missing value %d (%s)"
+	case BeNotAKeyValue:
+		return "%s, argument for %s, is not of the form KEY=VALUE"
+	case BeDuplicateKey:
+		return "key %s of %s was given more than once"
+	case BeNameCollision:
+		return "%s is used by both %s and %s"
+	case BeRemovedItem:
+		return "%s has been removed"
+	case BeExperimental:
+		return "%s is experimental%s"
+	case BeDeprecated:
+		return "%s is deprecated%s"
 	}
 	return "Unknown error"
 }
@@ -246,6 +319,26 @@ func codestr(code ParseErrCode) string {
 		return "NotAURL"
 	case BeNotAnIPv4:
 		return "NotAnIPv4"
+	case BeNotAChoice:
+		return "NotAChoice"
+	case BeUnterminatedQuote:
+		return "UnterminatedQuote"
+	case BeAmbiguousFlag:
+		return "AmbiguousFlag"
+	case BeMissingParam:
+		return "MissingParam"
+	case BeNotAKeyValue:
+		return "NotAKeyValue"
+	case BeDuplicateKey:
+		return "DuplicateKey"
+	case BeNameCollision:
+		return "NameCollision"
+	case BeRemovedItem:
+		return "RemovedItem"
+	case BeExperimental:
+		return "Experimental"
+	case BeDeprecated:
+		return "Deprecated"
 	}
 	return "Unknown error code"
 }
@@ -0,0 +1,37 @@
+package boa
+
+// ParseString parses a whole command line held in one string, as it
+// might come from a config file or a job queue. The string is split by
+// SplitArgs and parsed by ParseCommandLineArgs. Every ParseError in the
+// returned CLI has its Offset set to the byte offset in line of the
+// argument it is about, so callers can point at the mistake.
+func ParseString(cmds map[string]CmdLineItem, line string, opts ...Option) *CLI {
+	return NewParser(cmds, opts...).parseString(line)
+}
+
+func (p *Parser) parseString(line string) *CLI {
+	toks, pos, err := tokenize(line, false, p.conf.getenv)
+	if err != nil {
+		cli := &CLI{Items: make(map[string]CmdLineItem), conf: p.conf, index: p.index}
+		pe := err.(ParseError)
+		pe.Offset = pos
+		cli.SetError(pe)
+		return cli
+	}
+
+	args := make([]string, len(toks))
+	for i, t := range toks {
+		args[i] = t.text
+	}
+
+	cli := p.parse(args)
+	for _, errs := range [][]error{cli.Errs, cli.Warns} {
+		for i, e := range errs {
+			if pe, ok := e.(ParseError); ok && pe.Arg >= 0 && pe.Arg < len(toks) {
+				pe.Offset = toks[pe.Arg].pos
+				errs[i] = pe
+			}
+		}
+	}
+	return cli
+}
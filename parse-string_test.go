@@ -0,0 +1,33 @@
+package boa
+
+import "testing"
+
+func TestParseStringValueOffset(t *testing.T) {
+	items := map[string]CmdLineItem{
+		"--verbose": {Id: 1, Name: "--verbose", IsFlag: true},
+		"--count":   {Id: 2, Name: "--count", IsFlag: true, ParamType: TypeInt, ParamCount: 1},
+		"--tags":    {Id: 3, Name: "--tags", IsFlag: true, ParamType: TypeIntSlice, ParamCount: ZeroOrMore, Separator: ","},
+	}
+	p := NewParser(items)
+	for _, tc := range []struct {
+		line string
+		code ParseErrCode
+		arg  int
+		off  int
+	}{
+		{"--verbose  --count   x", BeNotAnInt, 2, 21},
+		{"--count=x", BeNotAnInt, 0, 0},
+		{"--tags 1 2,y --verbose", BeNotAnInt, 2, 9},
+		{"--count", BeNoRequiredInt, 0, 0},
+	} {
+		cli := p.ParseString(tc.line)
+		pe, ok := cli.LastError().(ParseError)
+		if !ok || pe.Code != tc.code {
+			t.Errorf("%q: error %v, want %s", tc.line, cli.LastError(), tc.code)
+			continue
+		}
+		if pe.Arg != tc.arg || pe.Offset != tc.off {
+			t.Errorf("%q: Arg %d Offset %d, want %d and %d", tc.line, pe.Arg, pe.Offset, tc.arg, tc.off)
+		}
+	}
+}
@@ -26,106 +26,270 @@ const (
 	OneOrNone  = -1   // fixed number of params but none are required
 )
 
-func ParseCommandLineArgs(cmds map[string]CmdLineItem, args []string) *CLI {
-	// first get rid of '=' signs
+func ParseCommandLineArgs(cmds map[string]CmdLineItem, args []string, opts ...Option) *CLI {
+	return NewParser(cmds, opts...).parse(args)
+}
+
+// parse does the work for ParseCommandLineArgs and Parse. It only reads
+// from p so any number of calls may run at once.
+func (p *Parser) parse(args []string) *CLI {
+	// first split --name=value at the '=' sign
 	// then check for compound flags eg. -doe; break up to -d -o -e
 	// the last one can have arguments depending on its definition
-	args = normalizeArgs(args)
+	var cli = CLI{Items: make(map[string]CmdLineItem, len(args)), conf: p.conf, index: p.index, app: p.info}
+	cmds := p.items
+	ix := p.index
+	for _, e := range p.errs {
+		cli.SetError(e)
+	}
+
+	// orig maps each argument back to its index in args
+	var orig []int
+	if cli.conf.responseFiles {
+		var errs []error
+		args, orig, errs = expandResponseFiles(args)
+		for _, e := range errs {
+			cli.SetError(e)
+		}
+	}
+	argIndex := func(i int) int {
+		if orig != nil {
+			return orig[i]
+		}
+		return i
+	}
+
+	raw := args
+	args, src, errs := cli.normalize(ix, raw)
+	for _, e := range errs {
+		if pe, ok := e.(ParseError); ok && pe.Arg >= 0 {
+			pe.Arg = argIndex(pe.Arg)
+			e = pe
+		}
+		cli.SetError(e)
+	}
+	cli.experimental = cli.experimentalEnabled(raw)
 
-	var cli = CLI{Items: make(map[string]CmdLineItem, len(args))}
 	var err error
 	var cm *CmdLineItem
+	var level *CmdLineItem // the last command seen, its policy applies to unknown args
 
 	n := 0
 	m := 0
 
-	for i := 0; i < len(args); i++ {
+	for n < len(args) {
 		a := args[n]
 		// deal with alias passed in
-		for _, c := range cmds {
-			if c.Alias == a {
-				a = c.Name
+		a = ix.canonical(a)
+		args[n] = a // in case the alias was transformed the proper value must be passed to getCmdValues
+
+		if a == "--" && cli.conf.passthrough {
+			cli.passed = append(cli.passed, raw[src[n]+1:]...)
+			break
+		}
+		if a == cli.conf.experimentalFlag && a != "" {
+			n++
+			continue
+		}
+		if (cli.conf.help || cli.conf.version) && cli.builtinAt(ix, a, n, args[n+1:], level) {
+			break
+		}
+		if !ix.isKnown(a) {
+			policy := unknownPolicy(cmds, level, cli.conf.onUnknown)
+			if cli.conf.noIntersperse && policy == UnknownCollect {
+				policy = UnknownStop
+			}
+			if policy == UnknownStop {
+				cli.unknown = append(cli.unknown, raw[src[n]:]...)
+				break
+			}
+			if policy == UnknownCollect {
+				cli.unknown = append(cli.unknown, a)
+				n++
+				continue
+			}
+			if cli.conf.noIntersperse {
+				// report the first and leave the rest alone
+				_, _, err = getCmdValues(ix, a, args[n:])
+				if pe, ok := err.(ParseError); ok {
+					pe.Arg = argIndex(src[n])
+					err = pe
+				}
+				cli.SetError(err)
 				break
 			}
 		}
-		args[n] = a // in case the alias was transformed the proper value must be passed to getCmdValues
 
-		m, cm, err = getCmdValues(cmds, a, args[n:])
-		n += m // skip the args consumed in the call above
+		at := argIndex(src[n])
+		m, cm, err = getCmdValues(ix, a, args[n:])
 		if err != nil {
+			if pe, ok := err.(ParseError); ok {
+				if pe.Arg >= 0 {
+					pe.Arg = argIndex(src[n+pe.Arg]) // the value that is wrong
+				} else {
+					pe.Arg = at
+				}
+				err = pe
+			}
 			cli.SetError(err)
 		}
+		if m < 1 {
+			m = 1 // always move on, a token is never read twice
+		}
+		n += m // skip the args consumed in the call above
 
+		if cm != nil && !cli.admit(cm, at) {
+			cm = nil
+		}
 		if cm != nil {
+			if prev, ok := cli.Items[cm.Name]; ok && cm.ParamType.IsMap() {
+				// a map item may be given more than once, --label a=1 --label b=2
+				if err := mergeMap(&prev, cm); err != nil {
+					cli.SetError(err)
+				}
+			}
 			cli.Items[cm.Name] = *cm
-		}
-
-		if n >= len(args) {
-			break
+			if !cm.IsFlag {
+				level = cm
+			}
+			if cli.conf.noIntersperse && positional(cm) && n < len(args) {
+				// the value of a command is the first positional, nothing after it is a flag
+				cli.unknown = append(cli.unknown, raw[src[n]:]...)
+				break
+			}
 		}
 	}
 
 	return &cli
 }
 
-func noeq(args []string) []string {
+// positional reports whether cm is a command that was given a value on
+// the command line.
+func positional(cm *CmdLineItem) bool {
+	return !cm.IsFlag && cm.ParamCount != 0 && cm.ParamType != TypeBool && cm.Source == SourceArgs && cm.Value != nil
+}
+
+// normalizeArgs breaks args into the tokens the parser works on and
+// returns, for every token, the index in args of the argument it came from.
+// Only flag tokens are changed. --name=joe becomes --name joe, splitting
+// at the first '=' only, so values may hold '=' and --name= keeps its
+// empty value. Compound flags such as -doe become -d -o -e and -doe=x
+// gives the last of them the value x. Anything that does not start with
+// a dash, '--' and negative numbers are passed through untouched.
+func normalizeArgs(args []string) ([]string, []int) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
 	var result []string
-	for _, a := range args {
-		a = strings.Trim(a, " ")
-		// take care of the pesky'=' sign as in --name=joe
-		if strings.Contains(a, "=") {
-			noeq := strings.Split(a, "=")
-			result = append(result, noeq...)
+	var orig []int
+	add := func(tok string, i int) {
+		result = append(result, tok)
+		orig = append(orig, i)
+	}
+
+	for i, a := range args {
+		if !isFlagToken(a) {
+			add(a, i)
+			continue
+		}
+
+		name, val, hasVal := strings.Cut(a, "=")
+		if strings.HasPrefix(name, "--") {
+			add(name, i)
 		} else {
-			result = append(result, a)
+			for _, r := range name[1:] {
+				add("-"+string(r), i)
+			}
+		}
+		if hasVal {
+			add(val, i)
 		}
 	}
 
-	return result
+	return result, orig
 }
 
-func normalizeArgs(args []string) []string {
-	if len(args) == 0 {
-		return nil
+func isFlagToken(a string) bool {
+	if len(a) < 2 || a[0] != '-' || a == "--" || a[1] == '=' {
+		return false
 	}
+	if _, err := strconv.ParseFloat(a, 64); err == nil {
+		return false
+	}
+	return true
+}
 
-	args = noeq(args)
-
-	var result []string
-	for _, a := range args {
-		if strings.HasPrefix(a, "--") {
-			result = append(result, a)
-			continue
-		} // double dash
+var phoneRe = regexp.MustCompile(`^(?:(?:\(?(?:00|\+)([1-4]\d\d|[1-9]\d?)\)?)?[\-\.\ \\\/]?)?((?:\(?\d{1,}\)?[\-\.\ \\\/]?){0,})(?:[\-\.\ \\\/]?(?:#|ext\.?|extension|x)[\-\.\ \\\/]?(\d+))?$`)
 
-		if !strings.HasPrefix(a, "-") {
-			result = append(result, a)
-			continue
-		} // no dashes
+// getCmdValues reads the item named a and its values from args, where
+// args[0] is a. An error about a value has its Arg set to the index in
+// args of the argument holding that value; other errors have Arg -1.
+func getCmdValues(ix *itemIndex, a string, args []string) (int, *CmdLineItem, error) {
+	i, cm, err := itemValues(ix, a, args)
+	if pe, ok := err.(ParseError); ok {
+		pe.Arg = valueArg(pe, args[:min(i, len(args))])
+		err = pe
+	}
+	return i, cm, err
+}
 
-		// take care of args entered as -abc; three separate args
-		a = strings.Trim(a, "- ")
-		for _, r := range a {
-			result = append(result, "-"+string(r))
+// valueArg finds the argument among args[1:] that holds the value an
+// error is about, preferring one that is the value alone to one that
+// has it as a piece, as with separators and KEY=VALUE. It returns -1 for
+// errors that are not about a value.
+func valueArg(pe ParseError, args []string) int {
+	switch pe.Code {
+	case BeNotABool, BeNotAnInt, BeNotAFloat, BeNotADate, BeNotATime, BeNotADuration, BeNotAnEmail,
+		BeNotAPhone, BeNotAPath, BeNotAURL, BeNotAnIPv4, BeNotAChoice, BeNotAKeyValue, BeDuplicateKey:
+	default:
+		return -1
+	}
+	fa := pe.fmtArgs()
+	if len(fa) == 0 {
+		return -1
+	}
+	v, ok := fa[0].(string)
+	if !ok {
+		return -1
+	}
+	for j := 1; j < len(args); j++ {
+		if args[j] == v {
+			return j
 		}
 	}
-
-	return result
+	for j := len(args) - 1; j >= 1; j-- {
+		if v != "" && strings.Contains(args[j], v) {
+			return j
+		}
+	}
+	return -1
 }
 
-func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *CmdLineItem, error) {
-	result, exist := cmds[a]
+func itemValues(ix *itemIndex, a string, args []string) (int, *CmdLineItem, error) {
+	result, exist := ix.lookup(a)
 	if !exist {
-		result, exist = cmds["--"+a]
+		result, exist = ix.lookup("--" + a)
 		if !exist {
 			return 1, nil, Errorf(BeInvalidCommand, a)
 		}
 	}
+	result.Source = SourceArgs
 
 	if len(args) < 1 {
 		return 0, nil, nil
 	}
 
+	isItem := func(a string) bool {
+		_, ok := ix.lookup(a)
+		return ok || ix.isBuiltin(a)
+	}
+	args = canonicalChildren(ix, &result, args)
+
+	if result.ParamCount > 1 && !result.ParamType.IsSlice() {
+		return parseTuple(args, &result, isItem)
+	}
+
 	// no args allowed
 	if result.ParamCount == 0 {
 		result.Value = true
@@ -133,17 +297,36 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 		return 1, &result, nil
 	}
 
+	// an optional value that was not given, and has no default, leaves
+	// Value nil rather than failing to convert an empty string. So does a
+	// built-in where the value should be: it is all that is wanted, and
+	// the item is not checked
+	k := 1
+	for k < len(args) && isChild(&result, args[k]) {
+		k++
+	}
+	optional := result.DefaultValue == "" && result.ParamCount < 0 && result.ParamCount > OneOrMore &&
+		!result.ParamType.IsSlice() && !result.ParamType.IsMap()
+	if optional && (k == len(args) || isItem(args[k])) || k < len(args) && ix.isBuiltin(args[k]) {
+		result.ChNames = append([]string(nil), args[1:k]...)
+		return k, &result, nil
+	}
+
 	switch result.ParamType {
+	case TypeBool: // a bool given a ParamCount is still a flag
+		result.Value = true
+		return 1, &result, nil
+
 	case TypeInt:
 		var n int64
 
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredInt, args[0]))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredInt, a))
 		if err != nil {
 			return i, &result, err
 		}
 		n, err = strconv.ParseInt(res, 10, 64)
 		if err != nil {
-			return i, &result, Errorf(BeNotAnInt, BeNotAnInt.String(), args[1], a)
+			return i, &result, Errorf(BeNotAnInt, res, a)
 		}
 
 		result.Value = int(n)
@@ -152,13 +335,13 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeFloat:
 		var n float64
 
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredFloat, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredFloat, a))
 		if err != nil {
 			return i, &result, err
 		}
 		n, err = strconv.ParseFloat(res, 64)
 		if err != nil {
-			return i, &result, Errorf(BeNotAFloat, BeNotAFloat.String(), args[1], a)
+			return i, &result, Errorf(BeNotAFloat, res, a)
 
 		}
 
@@ -166,7 +349,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 		return i, &result, nil
 
 	case TypeString:
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredString, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredString, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -174,50 +357,49 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 		return i, &result, nil
 
 	case TypeEmail:
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredEmail, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredEmail, a))
 		if err != nil {
 			return i, &result, err
 		}
 
 		email, err := mail.ParseAddress(res)
 		if err != nil || email == nil {
-			return i, &result, Errorf(BeNotAnEmail, BeNotAnEmail.String(), res, a)
+			return i, &result, Errorf(BeNotAnEmail, res, a)
 		}
 
 		result.Value = *email
 		return i, &result, nil
 
 	case TypePhone:
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredPhone, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredPhone, a))
 		if err != nil {
 			return i, &result, err
 		}
 
-		re := regexp.MustCompile(`^(?:(?:\(?(?:00|\+)([1-4]\d\d|[1-9]\d?)\)?)?[\-\.\ \\\/]?)?((?:\(?\d{1,}\)?[\-\.\ \\\/]?){0,})(?:[\-\.\ \\\/]?(?:#|ext\.?|extension|x)[\-\.\ \\\/]?(\d+))?$`)
-		b := re.MatchString(res)
+		b := phoneRe.MatchString(res)
 		if !b {
-			return i, &result, Errorf(BeNotAPhone, BeNotAPhone.String(), args[1], a)
+			return i, &result, Errorf(BeNotAPhone, res, a)
 		}
 
 		result.Value = res
 		return i, &result, nil
 
 	case TypeTime:
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredPhone, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredTime, a))
 		if err != nil {
 			return i, &result, err
 		}
 
 		timeval, err := time.Parse(time.Kitchen, res)
 		if err != nil {
-			return i, &result, Errorf(BeNotATime, args[1], a)
+			return i, &result, Errorf(BeNotATime, res, a)
 		}
 
 		result.Value = timeval
 		return i, &result, nil
 
 	case TypeTimeDuration:
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredDuration, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredDuration, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -228,13 +410,13 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 		// "ns", "us" (or "µs"), "ms", "s", "m", "h".
 		duration, err := time.ParseDuration(res)
 		if err != nil {
-			return i, &result, Errorf(BeNotADuration, args[1], a)
+			return i, &result, Errorf(BeNotADuration, res, a)
 		}
 		result.Value = duration
 		return i, &result, nil
 
 	case TypeDate:
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredDate, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredDate, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -245,47 +427,47 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 		const format = "Jan-02-2006"
 		dateval, err := time.Parse(format, res)
 		if err != nil {
-			return i, &result, Errorf(BeNotADate, args[1], a)
+			return i, &result, Errorf(BeNotADate, res, a)
 		}
 		result.Value = dateval
 		return i, &result, nil
 
 	case TypePath:
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredPath, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredPath, a))
 		if err != nil {
 			return i, &result, err
 		}
 
 		path, err := abspath.ExpandFrom(res)
 		if err != nil {
-			return i, &result, Errorf(BeNotAPath, args[1], a)
+			return i, &result, Errorf(BeNotAPath, res, a)
 		}
 		result.Value = path.String()
 		return i, &result, nil
 
 	case TypeURL:
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredURL, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredURL, a))
 		if err != nil {
 			return i, &result, err
 		}
 
 		url, err := url.ParseRequestURI(res)
 		if err != nil || url == nil {
-			return i, &result, Errorf(BeNotAURL, args[1], a)
+			return i, &result, Errorf(BeNotAURL, res, a)
 		}
 
-		result.Value = url
+		result.Value = *url
 		return i, &result, nil
 
 	case TypeIPv4:
-		i, res, err := parseArg(args, &result, Errorf(BeNoRequiredIPv4, a))
+		i, res, err := parseArg(args, &result, isItem, Errorf(BeNoRequiredIPv4, a))
 		if err != nil {
 			return i, &result, err
 		}
 
 		ip := net.ParseIP(res)
 		if ip == nil {
-			return i, &result, Errorf(BeNotAnIPv4, args[1], a)
+			return i, &result, Errorf(BeNotAnIPv4, res, a)
 		}
 		result.Value = ip
 		return i, &result, nil
@@ -295,7 +477,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeIntSlice:
 		var vals []int
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredInt, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredInt, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -314,7 +496,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeFloatSlice:
 		var vals []float64
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredFloat, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredFloat, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -333,7 +515,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeStringSlice:
 		var vals []string
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredString, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredString, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -345,7 +527,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeEmailSlice:
 		var vals []mail.Address
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredEmail, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredEmail, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -365,14 +547,13 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypePhoneSlice:
 		var vals []string
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredPhone, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredPhone, a))
 		if err != nil {
 			return i, &result, err
 		}
 
 		for _, v := range vs {
-			re := regexp.MustCompile(`^(?:(?:\(?(?:00|\+)([1-4]\d\d|[1-9]\d?)\)?)?[\-\.\ \\\/]?)?((?:\(?\d{1,}\)?[\-\.\ \\\/]?){0,})(?:[\-\.\ \\\/]?(?:#|ext\.?|extension|x)[\-\.\ \\\/]?(\d+))?$`)
-			b := re.MatchString(v)
+			b := phoneRe.MatchString(v)
 			if !b {
 				return i, &result, Errorf(BeNotAPhone, v, a)
 			}
@@ -384,7 +565,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeTimeSlice:
 		var vals []time.Time
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredTime, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredTime, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -402,7 +583,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeTimeDurationSlice:
 		var vals []time.Duration
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredDuration, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredDuration, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -420,7 +601,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeDateSlice:
 		var vals []time.Time
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredDate, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredDate, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -439,7 +620,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypePathSlice:
 		var vals []string
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredPath, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredPath, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -457,7 +638,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeURLSlice:
 		var vals []url.URL
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredURL, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredURL, a))
 		if err != nil {
 			return i, &result, err
 		}
@@ -465,7 +646,7 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 		for _, v := range vs {
 			url, err := url.ParseRequestURI(v)
 			if err != nil {
-				return 2, &result, Errorf(BeNotAURL, v, a)
+				return i, &result, Errorf(BeNotAURL, v, a)
 			}
 			if url != nil {
 				vals = append(vals, *url)
@@ -477,118 +658,215 @@ func getCmdValues(cmds map[string]CmdLineItem, a string, args []string) (int, *C
 	case TypeIPv4Slice:
 		var vals []net.IP
 
-		i, vs, err := parseSlice(args, &result, Errorf(BeNoRequiredIPv4, a))
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredIPv4, a))
+		if err != nil {
+			return i, &result, err
+		}
+
 		for _, v := range vs {
 			ip := net.ParseIP(v)
 			if ip == nil {
-				return i, &result, err
+				return i, &result, Errorf(BeNotAnIPv4, v, a)
 			}
 			vals = append(vals, ip)
 		}
 		result.Value = vals
 		return i, &result, nil
+
+	// map types---------------------------
+
+	case TypeStringMap:
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredString, a))
+		if err != nil {
+			return i, &result, err
+		}
+
+		m, err := parseMap(vs, &result, func(v string) (interface{}, error) { return v, nil })
+		result.Value = m
+		return i, &result, err
+
+	case TypeIntMap:
+		i, vs, err := parseSlice(args, &result, isItem, Errorf(BeNoRequiredInt, a))
+		if err != nil {
+			return i, &result, err
+		}
+
+		m, err := parseMap(vs, &result, func(v string) (interface{}, error) {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, Errorf(BeNotAnInt, v, a)
+			}
+			return int(n), nil
+		})
+		result.Value = m
+		return i, &result, err
 	}
 
-	return 1, nil, nil
+	return 1, &result, Errorf(BeUnsupportedType)
 }
 
-func parseArg(args []string, cmd *CmdLineItem, err error) (int, string, error) {
-	//get sub-commands if any for comparison against the args
-	var hasChild bool
-	if cmd.ChNames != nil {
-		hasChild = true
+func parseArg(args []string, cmd *CmdLineItem, isItem func(string) bool, err error) (int, string, error) {
+	i, res, err := parseArgValue(args, cmd, isItem, err)
+	if err == nil && res != "" && !isChoice(cmd, res) {
+		err = Errorf(BeNotAChoice, res, cmd.Name, strings.Join(cmd.Choices, ", "))
 	}
+	return i, res, err
+}
 
-	match := func(a string) (string, bool) {
-		for _, c := range cmd.ChNames {
-			if c == a {
-				return c, true
-			}
+// parseArgValue takes the value of an item from args[1:], after any sub
+// commands. An optional value is not given when the next argument names
+// an item; that argument is left for the item it names.
+func parseArgValue(args []string, cmd *CmdLineItem, isItem func(string) bool, err error) (int, string, error) {
+	// sub commands have to be used first, before the actual parameter
+	i := 0
+	if cmd.ChNames != nil {
+		var chl []string
+		for i+1 < len(args) && isChild(cmd, args[i+1]) {
+			chl = append(chl, args[i+1])
+			i++
 		}
-		return "", false
+		cmd.ChNames = chl // cmd.ChNames now holds the sub commands actually used in this instance
 	}
 
-	if len(args) <= 1 { // no arg given at the last cmd
+	optional := cmd.ParamCount < 0 && cmd.ParamCount > OneOrMore
+	if len(args) <= i+1 || optional && isItem(args[i+1]) { // no arg given for this cmd
 		if cmd.DefaultValue != "" { // use default value if defined
-			return 1, cmd.DefaultValue, nil
+			cmd.Source = SourceDefault
+			return i + 1, cmd.DefaultValue, nil
 		}
-		if cmd.ParamCount < 0 && cmd.ParamCount > -100 { // optional params 0 or more
-			return 1, "", err
+		if optional { // optional params 0 or more
+			return i + 1, "", nil
 		}
-		return 1, "", nil
+		return i + 1, "", err
 	}
-	if args[1] == "--" { // use DefaultValue even if it is an empty string
-		return 2, cmd.DefaultValue, nil
+	if args[i+1] == "--" { // use DefaultValue even if it is an empty string
+		cmd.Source = SourceDefault
+		return i + 2, cmd.DefaultValue, nil
 	}
-	var i = 0
-	if hasChild {
-		var chl []string
-		for _, a := range args[1:] {
-			if ch, ok := match(a); ok { // sub commands have to be used first, before the actual parameter
-				chl = append(chl, ch)
-				i++
-				continue
+	return i + 2, args[i+1], nil
+}
+
+func parseSlice(args []string, cmd *CmdLineItem, isItem func(string) bool, err error) (int, []string, error) {
+	i, vals, err := parseSliceValues(args, cmd, isItem, err)
+	if cmd.Separator != "" {
+		var split []string
+		for _, v := range vals {
+			split = append(split, splitEscaped(v, cmd.Separator)...)
+		}
+		vals = split
+	}
+	if err == nil && !cmd.ParamType.IsMap() {
+		for _, v := range vals {
+			if !isChoice(cmd, v) {
+				return i, vals, Errorf(BeNotAChoice, v, cmd.Name, strings.Join(cmd.Choices, ", "))
 			}
-			cmd.ChNames = chl // cmd.ChNames now holds the sub commands actually used in this instance
-			return 2 + i, args[i+1], nil
 		}
 	}
-	return 2 + i, args[1], nil
+	return i, vals, err
 }
 
-func parseSlice(args []string, cmd *CmdLineItem, err error) (int, []string, error) {
+// parseSliceValues takes values for a slice item from args[1:]. Leading
+// sub commands are taken first. Values are then taken until a '--', which
+// is consumed, until an argument that names an item, which is not, or
+// until the item has as many values as its ParamCount allows: exactly N
+// for ParamCount N, at most N for -N and any number for ZeroOrMore and
+// OneOrMore.
+func parseSliceValues(args []string, cmd *CmdLineItem, isItem func(string) bool, err error) (int, []string, error) {
 	var vals []string
-	//get sub-commands if any for comparison against the args
-	var hasChild bool
+	max := -1 // no limit
+	switch {
+	case cmd.ParamCount > 0:
+		max = cmd.ParamCount
+	case cmd.ParamCount < 0 && cmd.ParamCount > ZeroOrMore:
+		max = -cmd.ParamCount
+	}
+	optional := cmd.ParamCount < 0 && cmd.ParamCount > OneOrMore
+
+	j := 1
+	//get sub-commands if any, they have to be used before the values
 	if cmd.ChNames != nil {
-		hasChild = true
+		var chl []string
+		for j < len(args) && isChild(cmd, args[j]) {
+			chl = append(chl, args[j])
+			j++
+		}
+		cmd.ChNames = chl // cmd.ChNames now holds the sub commands actually used in this instance
 	}
 
-	match := func(a string) (string, bool) {
-		for _, c := range cmd.ChNames {
-			if c == a {
-				return c, true
-			}
+	for j < len(args) {
+		a := args[j]
+		if a == "--" {
+			j++
+			break
+		}
+		if isItem(a) || len(vals) == max {
+			break
 		}
-		return "", false
+		vals = append(vals, a)
+		j++
 	}
 
-	if len(args) < 2 {
+	if len(vals) == 0 {
 		if cmd.DefaultValue != "" {
-			vals = append(vals, cmd.DefaultValue)
-			return 1, vals, nil
+			cmd.Source = SourceDefault
+			return j, []string{cmd.DefaultValue}, nil
 		}
-		if cmd.ParamCount < 0 && cmd.ParamCount > OneOrMore { // params are optional
-			return 1, vals, nil
+		if optional {
+			return j, vals, nil
 		}
-		return 1, vals, err
+		return j, vals, err
 	}
+	if cmd.ParamCount > 0 && len(vals) < cmd.ParamCount {
+		return j, vals, err
+	}
+	return j, vals, nil
+}
 
-	j := 1
-	for n, arg := range args {
-		var chl []string
-		if hasChild {
-			for _, a := range args[n:] {
-				if a == "--" {
-					break
-				}
-				if ch, ok := match(a); ok { // sub commands have to be used first, before the actual parameter
-					chl = append(chl, ch)
-				}
+// canonicalChildren returns args with the sub commands that lead its
+// values, given by alias or in another case, replaced by their names so
+// isChild can find them. args itself is left alone.
+func canonicalChildren(ix *itemIndex, cmd *CmdLineItem, args []string) []string {
+	if len(cmd.ChNames) == 0 {
+		return args
+	}
+	var out []string
+	for j := 1; j < len(args); j++ {
+		name := ix.canonical(args[j])
+		if !isChild(cmd, name) {
+			break
+		}
+		if name != args[j] {
+			if out == nil {
+				out = append([]string(nil), args...)
 			}
+			out[j] = name
 		}
+	}
+	if out == nil {
+		return args
+	}
+	return out
+}
 
-		if hasChild {
-			cmd.ChNames = chl // cmd.ChNames now holds the sub commands actually used in this instance
+func isChild(cmd *CmdLineItem, a string) bool {
+	for _, c := range cmd.ChNames {
+		if c == a {
+			return true
 		}
+	}
+	return false
+}
 
-		if arg == "--" {
-			j++
-			break
+// isChoice reports whether v is acceptable for an item that restricts
+// its values to a list of Choices. Any value is acceptable otherwise.
+func isChoice(cmd *CmdLineItem, v string) bool {
+	if len(cmd.Choices) == 0 {
+		return true
+	}
+	for _, c := range cmd.Choices {
+		if c == v {
+			return true
 		}
-
-		vals = append(vals, arg)
-		j++
 	}
-	return j, vals, nil
+	return false
 }
@@ -0,0 +1,72 @@
+package boa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOptionalValueStopsAtItem(t *testing.T) {
+	items := map[string]CmdLineItem{
+		"--count":   {Id: 1, Name: "--count", IsFlag: true, ParamType: TypeInt, ParamCount: OneOrNone},
+		"--level":   {Id: 2, Name: "--level", IsFlag: true, ParamType: TypeInt, ParamCount: OneOrNone, DefaultValue: "3"},
+		"--verbose": {Id: 3, Name: "--verbose", Alias: "-v", IsFlag: true},
+	}
+	p := NewParser(items)
+	for _, args := range [][]string{
+		{"--count", "--verbose"},
+		{"--count", "-v"},
+		{"--level", "--verbose"},
+	} {
+		cli := p.Parse(args)
+		if cli.HasErrors() {
+			t.Errorf("%q: errors: %s", args, cli.Errors())
+		}
+		if _, ok := cli.Items["--verbose"]; !ok {
+			t.Errorf("%q: --verbose was taken as a value", args)
+		}
+	}
+
+	cli := p.Parse([]string{"--level", "--verbose"})
+	if n, _ := cli.Int("--level"); n != 3 || cli.Source("--level") != SourceDefault {
+		t.Errorf("--level = %d from %s, want 3 from default", n, cli.Source("--level"))
+	}
+	cli = p.Parse([]string{"--count", "5", "--verbose"})
+	if n, _ := cli.Int("--count"); n != 5 {
+		t.Errorf("--count = %d, want 5", n)
+	}
+}
+
+func TestChildByAlias(t *testing.T) {
+	items := map[string]CmdLineItem{
+		"deploy": {Id: 1, Name: "deploy", ParamType: TypeString, ParamCount: 1, ChNames: []string{"--now"}},
+		"--now":  {Id: 2, Name: "--now", Alias: "-n", IsFlag: true, ParName: "deploy"},
+	}
+	for _, tc := range []struct {
+		args []string
+		opts []Option
+	}{
+		{[]string{"deploy", "-n", "joe"}, nil},
+		{[]string{"DEPLOY", "--NOW", "joe"}, []Option{WithIgnoreCase()}},
+	} {
+		cli := NewParser(items, tc.opts...).Parse(tc.args)
+		if cli.HasErrors() {
+			t.Errorf("%q: errors: %s", tc.args, cli.Errors())
+			continue
+		}
+		it := cli.Items["deploy"]
+		if it.Value != "joe" || len(it.ChNames) != 1 || it.ChNames[0] != "--now" {
+			t.Errorf("%q: deploy = %v with %q, want joe with [--now]", tc.args, it.Value, it.ChNames)
+		}
+	}
+}
+
+func TestCollectItemsFromJSONDuplicate(t *testing.T) {
+	schema := `{"commands": [
+		{"Id": 1, "Name": "--name", "IsFlag": true},
+		{"Id": 2, "Name": "--name", "IsFlag": true, "ParamType": 1, "ParamCount": 1}
+	]}`
+	_, err := CollectItemsFromJSON([]byte(schema))
+	if err == nil || !strings.Contains(err.Error(), "commands[0] and commands[1]") {
+		t.Errorf("err = %v, want a collision between commands[0] and commands[1]", err)
+	}
+}
@@ -0,0 +1,116 @@
+package boa
+
+import (
+	"maps"
+	"sync"
+)
+
+// Parser holds everything about a set of items that stays the same from
+// one command line to the next: the options, the index of names and
+// aliases and the help text. Build it once and call Parse as often as
+// needed, from as many goroutines as needed.
+//
+// Every CLI a Parser returns has its own copy of the AllHelp map.
+type Parser struct {
+	items map[string]CmdLineItem
+	app   string
+	info  AppInfo
+	conf  config
+	index *itemIndex
+	errs  []error // found while building, reported by every parse
+
+	helpOnce sync.Once // the help is only formatted once it is wanted
+	help     map[string]string
+}
+
+// NewParser compiles items and opts into a Parser. items is copied, so
+// later changes to it do not reach the Parser. The BOA-APP-DATA record,
+// if there is one, is taken out and its alias becomes the Application,
+// unless WithApp gives the application another name.
+func NewParser(items map[string]CmdLineItem, opts ...Option) *Parser {
+	var C CLI
+	for _, opt := range opts {
+		opt(&C)
+	}
+
+	p := &Parser{items: make(map[string]CmdLineItem, len(items)), conf: C.conf}
+	for name, it := range items {
+		if name == AppDataName() {
+			p.info = appFromItem(it)
+			continue
+		}
+		p.items[name] = it
+	}
+	if p.conf.app != nil {
+		p.info = p.conf.app.clone()
+	}
+	p.app = p.info.Name
+	p.index, p.errs = newIndex(p.items, p.conf.ignoreCase)
+	p.index.help, p.index.version = p.conf.help, p.conf.version
+	return p
+}
+
+// NewParserFromJSON builds a Parser from a schema in the format FromJSON
+// reads, with the AppInfo AppFromJSON finds in it.
+func NewParserFromJSON(json []byte, opts ...Option) (*Parser, error) {
+	items, err := CollectItemsFromJSON(json)
+	if err != nil {
+		return nil, err
+	}
+	info, err := AppFromJSON(json)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]Option{WithApp(info)}, opts...) // a WithApp of the caller wins
+	return NewParser(items, opts...), nil
+}
+
+// Parse parses args the way FromJSON does: after the arguments are read
+// required and exclusive items are checked and the help is filled in.
+func (p *Parser) Parse(args []string) *CLI {
+	cli := p.parse(args)
+	p.finish(cli)
+	return cli
+}
+
+// ParseString is Parse for a command line held in one string. It works
+// like the ParseString function.
+func (p *Parser) ParseString(line string) *CLI {
+	cli := p.parseString(line)
+	p.finish(cli)
+	return cli
+}
+
+func (p *Parser) finish(cli *CLI) {
+	cli.Application = p.app
+	cli.AllHelp = maps.Clone(p.allHelp())
+	if cli.builtin != "" {
+		cli.printBuiltin(cli.conf.out)
+		return // nothing else is wanted, required items may well be missing
+	}
+	validateRequirements(p.items, cli)
+}
+
+// allHelp formats the help of every item the first time it is called,
+// so parsers that never show help do not pay for it.
+func (p *Parser) allHelp() map[string]string {
+	p.helpOnce.Do(func() {
+		p.help = allHelp(p.items, p.conf)
+	})
+	return p.help
+}
+
+// App returns the application info of p.
+func (p *Parser) App() AppInfo {
+	return p.info.clone()
+}
+
+// Items returns a copy of the items p parses, without the BOA-APP-DATA
+// record.
+func (p *Parser) Items() map[string]CmdLineItem {
+	items := make(map[string]CmdLineItem, len(p.items))
+	for name, it := range p.items {
+		items[name] = it
+	}
+	return items
+}
@@ -0,0 +1,61 @@
+package boa
+
+import "testing"
+
+func benchItems() map[string]CmdLineItem {
+	return map[string]CmdLineItem{
+		"deploy":    {Id: 1, Name: "deploy", ParamType: TypeString, ParamCount: 1, ShortHelp: "deploy: ship it", ChNames: []string{"--now"}},
+		"--now":     {Id: 2, Name: "--now", IsFlag: true, ParName: "deploy", ShortHelp: "--now: do not wait"},
+		"--verbose": {Id: 3, Name: "--verbose", Alias: "-v", IsFlag: true, ShortHelp: "--verbose: say more"},
+		"--count":   {Id: 4, Name: "--count", Alias: "-c", IsFlag: true, ParamType: TypeInt, ParamCount: 1, ShortHelp: "--count: how many"},
+		"--tags":    {Id: 5, Name: "--tags", IsFlag: true, ParamType: TypeStringSlice, ParamCount: ZeroOrMore, ShortHelp: "--tags: labels"},
+	}
+}
+
+var benchArgs = []string{"deploy", "--now", "prod", "-vc", "3", "--tags", "a", "b", "c"}
+
+func BenchmarkParse(b *testing.B) {
+	p := NewParser(benchItems())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if cli := p.Parse(benchArgs); cli.HasErrors() {
+			b.Fatal(cli.Errors())
+		}
+	}
+}
+
+func BenchmarkParseCommandLineArgs(b *testing.B) {
+	items := benchItems()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if cli := ParseCommandLineArgs(items, benchArgs); cli.HasErrors() {
+			b.Fatal(cli.Errors())
+		}
+	}
+}
+
+func TestParseCommandLineArgsNoHelp(t *testing.T) {
+	cli := ParseCommandLineArgs(benchItems(), benchArgs)
+	if cli.AllHelp != nil {
+		t.Errorf("help was formatted: %d entries", len(cli.AllHelp))
+	}
+	if cli := NewParser(benchItems()).Parse(benchArgs); len(cli.AllHelp) != len(benchItems()) {
+		t.Errorf("Parse gave help for %d items, want %d", len(cli.AllHelp), len(benchItems()))
+	}
+}
+
+func TestParseOwnHelp(t *testing.T) {
+	p := NewParser(benchItems())
+	cli := p.Parse(nil)
+	snap := cli.Snapshot()
+	cli.AllHelp["--verbose"] = "changed"
+
+	if h := p.Parse(nil).AllHelp["--verbose"]; h == "changed" {
+		t.Errorf("the help of one CLI reached the next")
+	}
+	if h := snap.Help("--verbose"); h == "changed" {
+		t.Errorf("the help of a CLI reached its snapshot")
+	}
+}
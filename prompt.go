@@ -0,0 +1,159 @@
+package boa
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Prompter asks for the values of required items that were not found on
+// the command line. In and Out can be any reader and writer so prompting
+// can be driven through pipes; NewPrompter wires them to the terminal.
+type Prompter struct {
+	In          io.Reader
+	Out         io.Writer
+	Interactive bool // when false nothing is asked and missing items stay errors
+	Retries     int  // attempts per item before giving up, 3 when zero
+
+	// Echo turns terminal echo off and back on around secret input.
+	// It is left nil when In is not a terminal.
+	Echo func(on bool)
+
+	mu sync.Mutex // one prompt at a time, a Parser may be shared by goroutines
+	rd *bufio.Reader
+}
+
+// NewPrompter returns a Prompter reading from stdin and writing to
+// stderr. It is only interactive when stdin is a terminal and the CI
+// environment variable is not set, so scripts and pipelines never block.
+func NewPrompter() *Prompter {
+	p := &Prompter{In: os.Stdin, Out: os.Stderr}
+	if isTerminal(os.Stdin) && os.Getenv("CI") == "" {
+		p.Interactive = true
+		p.Echo = sttyEcho
+	}
+	return p
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func sttyEcho(on bool) {
+	arg := "-echo"
+	if on {
+		arg = "echo"
+	}
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	_ = cmd.Run() // not fatal, the input is just not masked
+}
+
+// prompt asks for the value of it until one converts without error, the
+// retries are used up or the input ends. The answer goes through
+// getCmdValues so it is checked exactly as if it came from the command line.
+func (p *Prompter) prompt(cmds map[string]CmdLineItem, it CmdLineItem, th *Theme) (*CmdLineItem, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rd == nil {
+		p.rd = bufio.NewReader(p.In)
+	}
+	tries := p.Retries
+	if tries <= 0 {
+		tries = 3
+	}
+
+	err := error(Errorf(BeNoRequiredItem, it.Name))
+	for ; tries > 0; tries-- {
+		p.ask(it, th)
+		line, rerr := p.readLine(it.IsSecret)
+		line = strings.TrimSpace(line)
+		if rerr != nil && line == "" {
+			return nil, err
+		}
+
+		args := []string{it.Name}
+		if it.ParamCount == 0 || it.ParamType == TypeBool {
+			if b, _ := strconv.ParseBool(yesNo(line)); !b {
+				return nil, err
+			}
+		} else {
+			if n, e := strconv.Atoi(line); e == nil && n > 0 && n <= len(it.Choices) {
+				line = it.Choices[n-1]
+			}
+			if line == "" {
+				line = it.DefaultValue
+			}
+			if line == "" {
+				fmt.Fprintln(p.Out, "a value is required")
+				continue
+			}
+			if it.ParamType.IsSlice() || it.ParamCount > 1 {
+				args = append(args, strings.Fields(line)...)
+			} else {
+				args = append(args, line)
+			}
+		}
+
+		var cm *CmdLineItem
+		ix, _ := newIndex(cmds, false)
+		_, cm, err = getCmdValues(ix, it.Name, args)
+		if err == nil && cm != nil {
+			return cm, nil
+		}
+		if err != nil {
+			fmt.Fprintln(p.Out, err)
+		}
+	}
+	return nil, err
+}
+
+func (p *Prompter) ask(it CmdLineItem, th *Theme) {
+	if h := strings.TrimSpace(it.ShortHelp); h != "" {
+		fmt.Fprintln(p.Out, strings.SplitN(h, "\n", 2)[0])
+	}
+	for n, c := range it.Choices {
+		fmt.Fprintf(p.Out, "  %d) %s\n", n+1, c)
+	}
+
+	switch {
+	case it.ParamCount == 0 || it.ParamType == TypeBool:
+		fmt.Fprintf(p.Out, "%s [y/N]: ", it.Name)
+	case it.DefaultValue != "":
+		def := it.DefaultValue
+		if th != nil {
+			def = th.Default.paint(def)
+		}
+		fmt.Fprintf(p.Out, "%s (%s) [%s]: ", it.Name, TypeToString(it.ParamType), def)
+	default:
+		fmt.Fprintf(p.Out, "%s (%s): ", it.Name, TypeToString(it.ParamType))
+	}
+}
+
+func (p *Prompter) readLine(secret bool) (string, error) {
+	if secret && p.Echo != nil {
+		p.Echo(false)
+		defer func() {
+			p.Echo(true)
+			fmt.Fprintln(p.Out)
+		}()
+	}
+	return p.rd.ReadString('\n')
+}
+
+func yesNo(s string) string {
+	switch strings.ToLower(s) {
+	case "y", "yes":
+		return "true"
+	}
+	return s
+}
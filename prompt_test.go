@@ -0,0 +1,123 @@
+package boa
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func promptItems() map[string]CmdLineItem {
+	return map[string]CmdLineItem{
+		"--count": {Id: 1, Name: "--count", IsFlag: true, IsRequired: true, ParamType: TypeInt, ParamCount: 1, ShortHelp: "--count: how many"},
+	}
+}
+
+func TestPromptRetries(t *testing.T) {
+	var out bytes.Buffer
+	p := &Prompter{In: strings.NewReader("x\n\n7\n"), Out: &out, Interactive: true}
+	cli := NewParser(promptItems(), WithPrompter(p)).Parse(nil)
+
+	if cli.HasErrors() {
+		t.Fatalf("errors: %s", cli.Errors())
+	}
+	if n, _ := cli.Int("--count"); n != 7 {
+		t.Errorf("--count = %d, want 7", n)
+	}
+	if s := cli.Source("--count"); s != SourcePrompt {
+		t.Errorf("source = %s, want prompt", s)
+	}
+	if got := strings.Count(out.String(), "--count (Integer): "); got != 3 {
+		t.Errorf("asked %d times, want 3:\n%s", got, out.String())
+	}
+	for _, want := range []string{"NotAnInt", "a value is required"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPromptGivesUp(t *testing.T) {
+	var out bytes.Buffer
+	p := &Prompter{In: strings.NewReader("x\ny\nz\n"), Out: &out, Interactive: true, Retries: 2}
+	cli := NewParser(promptItems(), WithPrompter(p)).Parse(nil)
+
+	pe, ok := cli.LastError().(ParseError)
+	if !ok || pe.Code != BeNotAnInt {
+		t.Fatalf("last error = %v, want NotAnInt", cli.LastError())
+	}
+	if got := strings.Count(out.String(), "--count (Integer): "); got != 2 {
+		t.Errorf("asked %d times, want 2", got)
+	}
+}
+
+func TestPromptChoices(t *testing.T) {
+	items := map[string]CmdLineItem{
+		"--color": {Id: 1, Name: "--color", IsFlag: true, IsRequired: true, ParamType: TypeString, ParamCount: 1,
+			Choices: []string{"red", "green", "blue"}, ShortHelp: "--color: paint with"},
+	}
+	var out bytes.Buffer
+	p := &Prompter{In: strings.NewReader("2\n"), Out: &out, Interactive: true}
+	cli := NewParser(items, WithPrompter(p)).Parse(nil)
+
+	if s, _ := cli.String("--color"); s != "green" {
+		t.Errorf("--color = %q, want green", s)
+	}
+	want := "--color: paint with\n  1) red\n  2) green\n  3) blue\n--color (String): "
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestPromptNotInteractive(t *testing.T) {
+	var out bytes.Buffer
+	p := &Prompter{In: strings.NewReader("7\n"), Out: &out}
+	cli := NewParser(promptItems(), WithPrompter(p)).Parse(nil)
+
+	pe, ok := cli.LastError().(ParseError)
+	if !ok || pe.Code != BeNoRequiredItem {
+		t.Fatalf("last error = %v, want NoRequiredItem", cli.LastError())
+	}
+	if out.Len() != 0 {
+		t.Errorf("asked although not interactive: %q", out.String())
+	}
+}
+
+func TestPromptShared(t *testing.T) {
+	const n = 8
+	var in strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&in, "%d\n", i)
+	}
+	p := &Prompter{In: strings.NewReader(in.String()), Out: &bytes.Buffer{}, Interactive: true}
+	parser := NewParser(promptItems(), WithPrompter(p))
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if cli := parser.Parse(nil); cli.HasErrors() {
+				t.Errorf("errors: %s", cli.Errors())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPromptTuple(t *testing.T) {
+	items := map[string]CmdLineItem{
+		"--size": {Id: 1, Name: "--size", IsFlag: true, IsRequired: true, ParamType: TypeInt, ParamCount: 2},
+	}
+	p := &Prompter{In: strings.NewReader("3 4\n"), Out: &bytes.Buffer{}, Interactive: true}
+	cli := NewParser(items, WithPrompter(p)).Parse(nil)
+
+	if cli.HasErrors() {
+		t.Fatalf("errors: %s", cli.Errors())
+	}
+	if got := cli.Items["--size"].Value; !reflect.DeepEqual(got, []interface{}{3, 4}) {
+		t.Errorf("--size = %#v, want [3 4]", got)
+	}
+}
@@ -0,0 +1,78 @@
+package boa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// expandResponseFiles replaces every @file argument with the arguments
+// read from file. The file is split like a shell would split it and a
+// '#' at the start of a word comments out the rest of the line. A file
+// may name other files the same way; those are found relative to the
+// file that names them. '@@' at the start of an argument stands for a
+// literal '@'. The index in args each result came from is returned too.
+func expandResponseFiles(args []string) ([]string, []int, []error) {
+	var result []string
+	var orig []int
+	var errs []error
+	for i, a := range args {
+		result, errs = expandArg(a, "", 0, nil, result, errs)
+		for len(orig) < len(result) {
+			orig = append(orig, i)
+		}
+	}
+	return result, orig, errs
+}
+
+// expandArg appends the expansion of a to result. from and line tell
+// where a was read, they are empty for arguments from the command line.
+// stack holds the files being read to catch files that include themselves.
+func expandArg(a, from string, line int, stack []string, result []string, errs []error) ([]string, []error) {
+	where := func(msg string) string {
+		if from == "" {
+			return msg
+		}
+		return fmt.Sprintf("%s:%d: %s", from, line, msg)
+	}
+
+	if strings.HasPrefix(a, "@@") {
+		return append(result, a[1:]), errs
+	}
+	if !strings.HasPrefix(a, "@") || len(a) == 1 {
+		return append(result, a), errs
+	}
+
+	name := a[1:]
+	if from != "" && !filepath.IsAbs(name) {
+		name = filepath.Join(filepath.Dir(from), name)
+	}
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return result, append(errs, Errorf(BeFileReadError, where(err.Error())))
+	}
+	for _, s := range stack {
+		if s == abs {
+			return result, append(errs, Errorf(BeFileReadError, where(name+" includes itself")))
+		}
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return result, append(errs, Errorf(BeFileReadError, where(err.Error())))
+	}
+	text := string(b)
+	toks, pos, err := tokenize(text, true, nil)
+	if err != nil {
+		n := strings.Count(text[:pos], "\n") + 1
+		errs = append(errs, Errorf(BeFileReadError, fmt.Sprintf("%s:%d: unterminated quote", name, n)))
+	}
+
+	stack = append(stack, abs)
+	for _, t := range toks {
+		n := strings.Count(text[:t.pos], "\n") + 1
+		result, errs = expandArg(t.text, name, n, stack, result, errs)
+	}
+	return result, errs
+}
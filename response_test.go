@@ -0,0 +1,111 @@
+package boa
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes each name to its text in a new temporary directory
+// and returns the directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, text := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestExpandResponseFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"args":        "--name 'two words' # a comment @ignored\n\"a \\\"quoted\\\" one\"\n@sub/more\n",
+		"sub/more":    "--count 3 @last # found next to more\n",
+		"sub/last":    "--last\n",
+		"loop":        "x\n@loop2\n",
+		"loop2":       "y\n@loop\n",
+		"quote":       "ok\n\n'open\n",
+		"missing-one": "@nowhere\n",
+	})
+	at := func(name string) string { return "@" + filepath.Join(dir, name) }
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		want []string
+		orig []int
+		errs []string // pieces of the error messages, in order
+	}{
+		{
+			name: "nested",
+			args: []string{"first", at("args"), "@@literal"},
+			want: []string{"first", "--name", "two words", `a "quoted" one`, "--count", "3", "--last", "@literal"},
+			orig: []int{0, 1, 1, 1, 1, 1, 1, 2},
+		},
+		{
+			name: "cycle",
+			args: []string{at("loop")},
+			want: []string{"x", "y"},
+			orig: []int{0, 0},
+			errs: []string{filepath.Join(dir, "loop2") + ":2: " + filepath.Join(dir, "loop") + " includes itself"},
+		},
+		{
+			name: "unterminated quote",
+			args: []string{at("quote")},
+			want: []string{"ok"},
+			orig: []int{0},
+			errs: []string{filepath.Join(dir, "quote") + ":3: unterminated quote"},
+		},
+		{
+			name: "missing file",
+			args: []string{at("missing-one")},
+			errs: []string{filepath.Join(dir, "missing-one") + ":1: ", "nowhere"},
+		},
+		{
+			name: "lone @",
+			args: []string{"@"},
+			want: []string{"@"},
+			orig: []int{0},
+		},
+	} {
+		got, orig, errs := expandResponseFiles(tc.args)
+		if !reflect.DeepEqual(got, tc.want) || !reflect.DeepEqual(orig, tc.orig) {
+			t.Errorf("%s: got %q from %v, want %q from %v", tc.name, got, orig, tc.want, tc.orig)
+		}
+		if len(errs) != 0 && tc.errs == nil || len(errs) == 0 && tc.errs != nil {
+			t.Errorf("%s: errors %v, want %q", tc.name, errs, tc.errs)
+			continue
+		}
+		for _, e := range errs {
+			pe, ok := e.(ParseError)
+			if !ok || pe.Code != BeFileReadError {
+				t.Errorf("%s: error %v, want FileReadError", tc.name, e)
+			}
+			for _, piece := range tc.errs {
+				if !strings.Contains(e.Error(), piece) {
+					t.Errorf("%s: error %q lacks %q", tc.name, e, piece)
+				}
+			}
+		}
+	}
+}
+
+func TestResponseFileErrorOffset(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"args": "--count\nx\n"})
+	items := map[string]CmdLineItem{
+		"--count": {Id: 1, Name: "--count", IsFlag: true, ParamType: TypeInt, ParamCount: 1},
+	}
+	cli := NewParser(items, WithResponseFiles()).Parse([]string{"--count", "1", "@" + filepath.Join(dir, "args")})
+	pe, ok := cli.LastError().(ParseError)
+	if !ok || pe.Code != BeNotAnInt || pe.Arg != 2 {
+		t.Errorf("last error = %#v, want NotAnInt at arg 2, the @file", cli.LastError())
+	}
+}
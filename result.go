@@ -0,0 +1,374 @@
+package boa
+
+import (
+	"maps"
+	"net"
+	"net/mail"
+	"net/url"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Result is a read-only snapshot of a parsed CLI. Nothing in it can be
+// changed after it is made, every slice or map it hands out is a copy,
+// so one Result can be shared by any number of goroutines. With makes a
+// changed copy and leaves the original alone.
+type Result struct {
+	cli *CLI // private copy, never written after Snapshot
+}
+
+// Snapshot copies the current state of C into a Result.
+func (C *CLI) Snapshot() *Result {
+	snap := &CLI{
+		Application: C.Application,
+		Items:       make(map[string]CmdLineItem, len(C.Items)),
+		AllHelp:     maps.Clone(C.AllHelp),
+		conf:        C.conf,
+		index:       C.index,
+		unknown:     slices.Clone(C.unknown),
+		passed:      slices.Clone(C.passed),
+		app:         C.app,
+		builtin:     C.builtin,
+		topic:       C.topic,
+	}
+	for name, it := range C.Items {
+		snap.Items[name] = cloneItem(it)
+	}
+	C.mu.Lock()
+	snap.Errs = slices.Clone(C.Errs)
+	snap.Warns = slices.Clone(C.Warns)
+	C.mu.Unlock()
+	return &Result{cli: snap}
+}
+
+// With returns a copy of R in which item has value. The item does not
+// have to be in R; when it is not, it is added.
+func (R *Result) With(item string, value interface{}) *Result {
+	cli := &CLI{
+		Application: R.cli.Application,
+		Items:       maps.Clone(R.cli.Items),
+		AllHelp:     R.cli.AllHelp,
+		Errs:        R.cli.Errs,
+		Warns:       R.cli.Warns,
+		conf:        R.cli.conf,
+		index:       R.cli.index,
+		unknown:     R.cli.unknown,
+		passed:      R.cli.passed,
+		app:         R.cli.app,
+		builtin:     R.cli.builtin,
+		topic:       R.cli.topic,
+	}
+	if cli.Items == nil {
+		cli.Items = make(map[string]CmdLineItem)
+	}
+
+	it, ok := cli.Items[item]
+	if !ok && cli.index != nil {
+		it, ok = cli.index.lookup(item)
+	}
+	if !ok {
+		it = CmdLineItem{Name: item}
+	}
+	it.Value = cloneValue(value)
+	cli.Items[it.Name] = it
+	return &Result{cli: cli}
+}
+
+// Item returns a copy of the parsed item called name.
+func (R *Result) Item(name string) (CmdLineItem, bool) {
+	it, ok := R.cli.Items[name]
+	if !ok {
+		return CmdLineItem{}, false
+	}
+	return cloneItem(it), true
+}
+
+// Names returns the names of the items found, sorted.
+func (R *Result) Names() []string {
+	names := make([]string, 0, len(R.cli.Items))
+	for name := range R.cli.Items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (R *Result) Application() string {
+	return R.cli.Application
+}
+
+func (R *Result) Errs() []error {
+	return slices.Clone(R.cli.Errs)
+}
+
+func (R *Result) Errors() string {
+	return R.cli.Errors()
+}
+
+func (R *Result) HasErrors() bool {
+	return R.cli.HasErrors()
+}
+
+func (R *Result) Warns() []error {
+	return slices.Clone(R.cli.Warns)
+}
+
+func (R *Result) Warnings() string {
+	return R.cli.Warnings()
+}
+
+func (R *Result) HasWarnings() bool {
+	return R.cli.HasWarnings()
+}
+
+func (R *Result) LastError() error {
+	return R.cli.LastError()
+}
+
+func (R *Result) Unknown() []string {
+	return slices.Clone(R.cli.unknown)
+}
+
+func (R *Result) Passthrough() []string {
+	return slices.Clone(R.cli.passed)
+}
+
+func (R *Result) Args() []string {
+	return R.cli.Args()
+}
+
+func (R *Result) CommandLine() string {
+	return R.cli.CommandLine()
+}
+
+func (R *Result) Usage() string {
+	return R.cli.Usage()
+}
+
+func (R *Result) App() AppInfo {
+	return R.cli.App()
+}
+
+func (R *Result) Man() string {
+	return R.cli.Man()
+}
+
+func (R *Result) Markdown() string {
+	return R.cli.Markdown()
+}
+
+func (R *Result) Builtin() string {
+	return R.cli.Builtin()
+}
+
+func (R *Result) CommandUsage(command string) string {
+	return R.cli.CommandUsage(command)
+}
+
+func (R *Result) VersionString() string {
+	return R.cli.VersionString()
+}
+
+func (R *Result) Source(item string) Source {
+	return R.cli.Source(item)
+}
+
+func (R *Result) Help(topic string, ty ...HelpType) string {
+	return R.cli.Help(topic, ty...)
+}
+
+func (R *Result) Bool(item string) (bool, bool) {
+	return R.cli.Bool(item)
+}
+
+func (R *Result) String(item string) (string, bool) {
+	return R.cli.String(item)
+}
+
+func (R *Result) Int(item string) (int, bool) {
+	return R.cli.Int(item)
+}
+
+func (R *Result) Float(item string) (float64, bool) {
+	return R.cli.Float(item)
+}
+
+func (R *Result) Time(item string) (time.Time, bool) {
+	return R.cli.Time(item)
+}
+
+func (R *Result) Date(item string) (time.Time, bool) {
+	return R.cli.Date(item)
+}
+
+func (R *Result) Path(item string) (string, bool) {
+	return R.cli.Path(item)
+}
+
+func (R *Result) Email(item string) (mail.Address, bool) {
+	return R.cli.Email(item)
+}
+
+func (R *Result) TimeDuration(item string) (time.Duration, bool) {
+	return R.cli.TimeDuration(item)
+}
+
+func (R *Result) URL(item string) (url.URL, bool) {
+	u, ok := R.cli.URL(item)
+	return cloneURL(u), ok
+}
+
+func (R *Result) IPv4(item string) (net.IP, bool) {
+	ip, ok := R.cli.IPv4(item)
+	return slices.Clone(ip), ok
+}
+
+func (R *Result) StringSlice(item string) ([]string, bool) {
+	s, ok := R.cli.StringSlice(item)
+	return slices.Clone(s), ok
+}
+
+func (R *Result) IntSlice(item string) ([]int, bool) {
+	s, ok := R.cli.IntSlice(item)
+	return slices.Clone(s), ok
+}
+
+func (R *Result) FloatSlice(item string) ([]float64, bool) {
+	s, ok := R.cli.FloatSlice(item)
+	return slices.Clone(s), ok
+}
+
+func (R *Result) TimeSlice(item string) ([]time.Time, bool) {
+	s, ok := R.cli.TimeSSlice(item)
+	return slices.Clone(s), ok
+}
+
+func (R *Result) TimeDurationSlice(item string) ([]time.Duration, bool) {
+	s, ok := R.cli.TimeDurationSlice(item)
+	return slices.Clone(s), ok
+}
+
+func (R *Result) DateSlice(item string) ([]time.Time, bool) {
+	s, ok := R.cli.DateSlice(item)
+	return slices.Clone(s), ok
+}
+
+func (R *Result) PathSlice(item string) ([]string, bool) {
+	s, ok := R.cli.PathSlice(item)
+	return slices.Clone(s), ok
+}
+
+func (R *Result) EmailSlice(item string) ([]mail.Address, bool) {
+	s, ok := R.cli.EmailSlice(item)
+	return slices.Clone(s), ok
+}
+
+func (R *Result) IPv4Slice(item string) ([]net.IP, bool) {
+	s, ok := R.cli.IPv4Slice(item)
+	if !ok {
+		return nil, false
+	}
+	return cloneValue(s).([]net.IP), true
+}
+
+func (R *Result) URLSlice(item string) ([]url.URL, bool) {
+	s, ok := R.cli.URLSlice(item)
+	if !ok {
+		return nil, false
+	}
+	return cloneValue(s).([]url.URL), true
+}
+
+func (R *Result) StringMap(item string) (map[string]string, bool) {
+	m, ok := R.cli.StringMap(item)
+	return maps.Clone(m), ok
+}
+
+func (R *Result) IntMap(item string) (map[string]int, bool) {
+	m, ok := R.cli.IntMap(item)
+	return maps.Clone(m), ok
+}
+
+func (R *Result) Tuple(item string) ([]interface{}, bool) {
+	t, ok := R.cli.Tuple(item)
+	if !ok {
+		return nil, false
+	}
+	return cloneValue(t).([]interface{}), true
+}
+
+func (R *Result) Param(item, name string) (interface{}, bool) {
+	v, ok := R.cli.Param(item, name)
+	return cloneValue(v), ok
+}
+
+// cloneItem copies an item deep enough that nothing in the copy shares
+// memory the caller could write to.
+func cloneItem(it CmdLineItem) CmdLineItem {
+	it.Value = cloneValue(it.Value)
+	it.Errors = slices.Clone(it.Errors)
+	it.ChNames = slices.Clone(it.ChNames)
+	it.Aliases = slices.Clone(it.Aliases)
+	it.Choices = slices.Clone(it.Choices)
+	it.Params = slices.Clone(it.Params)
+	it.RenamedFrom = slices.Clone(it.RenamedFrom)
+	it.NoLint = slices.Clone(it.NoLint)
+	it.Locales = maps.Clone(it.Locales)
+	it.Examples = slices.Clone(it.Examples)
+	return it
+}
+
+// cloneValue copies the values the parser produces that hold slices,
+// maps or pointers. Everything else is returned as it is.
+func cloneValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []string:
+		return slices.Clone(v)
+	case []int:
+		return slices.Clone(v)
+	case []float64:
+		return slices.Clone(v)
+	case []time.Time:
+		return slices.Clone(v)
+	case []time.Duration:
+		return slices.Clone(v)
+	case []mail.Address:
+		return slices.Clone(v)
+	case net.IP:
+		return slices.Clone(v)
+	case []net.IP:
+		ips := make([]net.IP, len(v))
+		for i, ip := range v {
+			ips[i] = slices.Clone(ip)
+		}
+		return ips
+	case url.URL:
+		return cloneURL(v)
+	case []url.URL:
+		urls := make([]url.URL, len(v))
+		for i, u := range v {
+			urls[i] = cloneURL(u)
+		}
+		return urls
+	case map[string]string:
+		return maps.Clone(v)
+	case map[string]int:
+		return maps.Clone(v)
+	case []interface{}:
+		t := make([]interface{}, len(v))
+		for i, e := range v {
+			t[i] = cloneValue(e)
+		}
+		return t
+	}
+	return v
+}
+
+func cloneURL(u url.URL) url.URL {
+	if u.User != nil {
+		user := *u.User
+		u.User = &user
+	}
+	return u
+}
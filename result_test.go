@@ -0,0 +1,98 @@
+package boa
+
+import (
+	"sync"
+	"testing"
+)
+
+func resultItems() map[string]CmdLineItem {
+	return map[string]CmdLineItem{
+		"--count": {Id: 1, Name: "--count", IsFlag: true, ParamType: TypeInt, ParamCount: 1},
+		"--tags":  {Id: 2, Name: "--tags", IsFlag: true, ParamType: TypeStringSlice, ParamCount: ZeroOrMore},
+		"--env":   {Id: 3, Name: "--env", IsFlag: true, ParamType: TypeStringMap, ParamCount: ZeroOrMore},
+		"--size":  {Id: 4, Name: "--size", IsFlag: true, ParamType: TypeInt, ParamCount: 2},
+	}
+}
+
+var resultArgs = []string{"--count", "3", "--tags", "a", "b", "--env", "k=v", "--size", "1", "2", "--bad"}
+
+// TestResultShared is meant for go test -race: one Parser and one Result
+// are used by many goroutines at once.
+func TestResultShared(t *testing.T) {
+	p := NewParser(resultItems())
+	shared := p.Parse(resultArgs).Snapshot()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := p.Parse(resultArgs).Snapshot()
+			if !r.HasErrors() || r.Errors() != shared.Errors() {
+				t.Errorf("errors %q, want %q", r.Errors(), shared.Errors())
+			}
+			if n, _ := shared.Int("--count"); n != 3 {
+				t.Errorf("--count = %d, want 3", n)
+			}
+			tags, _ := shared.StringSlice("--tags")
+			tags[0] = "changed"
+			env, _ := shared.StringMap("--env")
+			env["k"] = "changed"
+			_ = shared.With("--count", i)
+			_ = shared.Names()
+			_ = shared.Errs()
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestResultWith(t *testing.T) {
+	r := NewParser(resultItems()).Parse(resultArgs).Snapshot()
+	r2 := r.With("--count", 9).With("--new", "x")
+
+	if n, _ := r.Int("--count"); n != 3 {
+		t.Errorf("original --count = %d, want 3", n)
+	}
+	if _, ok := r.Item("--new"); ok {
+		t.Errorf("--new was added to the original")
+	}
+	if n, _ := r2.Int("--count"); n != 9 {
+		t.Errorf("copy --count = %d, want 9", n)
+	}
+	if s, _ := r2.String("--new"); s != "x" {
+		t.Errorf("copy --new = %q, want x", s)
+	}
+}
+
+func TestResultCopies(t *testing.T) {
+	r := NewParser(resultItems()).Parse(resultArgs).Snapshot()
+
+	tags, _ := r.StringSlice("--tags")
+	tags[0] = "changed"
+	env, _ := r.StringMap("--env")
+	env["k"] = "changed"
+	size, _ := r.Tuple("--size")
+	size[0] = 99
+	it, _ := r.Item("--tags")
+	it.Value.([]string)[1] = "changed"
+	names := r.Names()
+	names[0] = "changed"
+	errs := r.Errs()
+	errs[0] = nil
+
+	if tags, _ := r.StringSlice("--tags"); tags[0] != "a" || tags[1] != "b" {
+		t.Errorf("--tags = %q, want [a b]", tags)
+	}
+	if env, _ := r.StringMap("--env"); env["k"] != "v" {
+		t.Errorf("--env = %v, want k=v", env)
+	}
+	if size, _ := r.Tuple("--size"); size[0] != 1 {
+		t.Errorf("--size = %v, want [1 2]", size)
+	}
+	if r.Names()[0] == "changed" {
+		t.Errorf("Names shares its slice")
+	}
+	if r.Errs()[0] == nil {
+		t.Errorf("Errs shares its slice")
+	}
+}
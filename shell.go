@@ -0,0 +1,372 @@
+package boa
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Shell keeps a session open over a set of items and parses every line
+// typed into it as a separate command line. An app usually starts one
+// when its own 'shell' command is given:
+//
+//	if _, ok := cli.Items["shell"]; ok {
+//		boa.NewShell(items, run).Loop()
+//	}
+//
+// Besides the items the shell knows help [topic], history and exit.
+type Shell struct {
+	Items       map[string]CmdLineItem
+	Prompt      string
+	In          io.Reader
+	Out         io.Writer
+	Run         func(cli *CLI) error // called for each line that parsed without errors
+	Options     []Option             // passed to ParseCommandLineArgs for every line
+	History     []string
+	HistoryFile string // when set history is loaded from and saved to this file
+
+	// Raw turns on line editing: tab completion and history recall with
+	// the arrow keys. It needs In to be a terminal.
+	Raw bool
+
+	help map[string]string
+	rd   *bufio.Reader
+}
+
+var errExit = errors.New("exit")
+
+// conf is the config the Options of s make.
+func (s *Shell) conf() config {
+	var C CLI
+	for _, opt := range s.Options {
+		opt(&C)
+	}
+	return C.conf
+}
+
+// NewShell returns a shell on stdin and stdout that calls run for every
+// command line that parses. Line editing is on when stdin is a terminal.
+func NewShell(items map[string]CmdLineItem, run func(cli *CLI) error) *Shell {
+	return &Shell{
+		Items:  items,
+		Prompt: "> ",
+		In:     os.Stdin,
+		Out:    os.Stdout,
+		Run:    run,
+		Raw:    isTerminal(os.Stdin),
+	}
+}
+
+// Loop reads and runs lines until exit is typed or the input ends.
+func (s *Shell) Loop() error {
+	s.rd = bufio.NewReader(s.In)
+	s.help = allHelp(s.Items, s.conf())
+	s.loadHistory()
+	defer s.saveHistory()
+
+	if s.Raw {
+		setRaw(true)
+		defer setRaw(false)
+	}
+
+	for {
+		line, err := s.readLine()
+		if strings.TrimSpace(line) != "" {
+			if xerr := s.Exec(line); xerr == errExit {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Exec runs a single line as if it was typed into the shell.
+func (s *Shell) Exec(line string) error {
+	if s.help == nil {
+		s.help = allHelp(s.Items, s.conf())
+	}
+	args, err := SplitArgs(line)
+	if err != nil {
+		s.println(err.Error())
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	s.History = append(s.History, line)
+
+	switch args[0] {
+	case "exit", "quit":
+		return errExit
+	case "history":
+		for n, h := range s.History {
+			s.println(fmt.Sprintf("%4d  %s", n+1, h))
+		}
+		return nil
+	case "help":
+		s.printHelp(args[1:])
+		return nil
+	}
+
+	// each line gets its own CLI so nothing leaks from one to the next
+	cli := ParseCommandLineArgs(s.Items, args, s.Options...)
+	cli.AllHelp = maps.Clone(s.help)
+	if cli.Builtin() != "" {
+		cli.printBuiltin(s.Out)
+		return nil
+	}
+	validateRequirements(s.Items, cli)
+	if cli.HasErrors() {
+		s.println(cli.Errors())
+		return cli.LastError()
+	}
+	if s.Run == nil {
+		return nil
+	}
+	if err := s.Run(cli); err != nil {
+		s.println(err.Error())
+		return err
+	}
+	return nil
+}
+
+func (s *Shell) printHelp(topics []string) {
+	if len(topics) == 0 {
+		var names []string
+		for name, it := range s.Items {
+			if _, shown := s.help[name]; shown && it.ParName == "" {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			s.println(strings.SplitN(s.help[name], "\n", 2)[0])
+		}
+		return
+	}
+
+	ix, _ := newIndex(s.Items, false)
+	cli := CLI{Items: s.Items, AllHelp: s.help, conf: s.conf(), index: ix}
+	for _, t := range topics {
+		if h := cli.Help(t); h != "" {
+			s.println(strings.TrimRight(h, "\n"))
+			continue
+		}
+		s.println(Errorf(BeInvalidCommand, t).Error())
+	}
+}
+
+// Complete returns the words that could finish the last word of line.
+// After a command that has sub items only those and the flags are offered.
+func (s *Shell) Complete(line string) []string {
+	words := strings.Fields(line)
+	prefix := ""
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		prefix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var pool []string
+	var parent *CmdLineItem
+	ix, _ := newIndex(s.Items, false)
+	for _, w := range words {
+		if it, ok := ix.lookup(w); ok && len(it.ChNames) > 0 {
+			parent = &it
+		}
+	}
+	if parent != nil {
+		for _, ch := range parent.ChNames {
+			if it, ok := s.Items[ch]; ok && (it.IsHidden || it.IsDeleted) {
+				continue
+			}
+			pool = append(pool, ch)
+		}
+	}
+	for _, it := range s.Items {
+		if it.IsHidden || it.IsDeleted {
+			continue
+		}
+		if parent == nil && it.ParName != "" && !it.IsFlag {
+			continue
+		}
+		if parent != nil && !it.IsFlag {
+			continue
+		}
+		pool = append(pool, it.Name)
+		pool = append(pool, it.AllAliases()...)
+	}
+	if len(words) == 0 {
+		pool = append(pool, "help", "history", "exit")
+	}
+
+	var found []string
+	seen := make(map[string]bool)
+	for _, p := range pool {
+		if strings.HasPrefix(p, prefix) && !seen[p] {
+			seen[p] = true
+			found = append(found, p)
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+func (s *Shell) println(str string) {
+	if s.Raw {
+		str = strings.ReplaceAll(str, "\n", "\r\n")
+		fmt.Fprint(s.Out, str+"\r\n")
+		return
+	}
+	fmt.Fprintln(s.Out, str)
+}
+
+func (s *Shell) readLine() (string, error) {
+	fmt.Fprint(s.Out, s.Prompt)
+	if !s.Raw {
+		line, err := s.rd.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err
+	}
+	return s.editLine()
+}
+
+// editLine reads one line from a terminal in raw mode. It knows about
+// backspace, tab, the up and down arrows, ctrl-c and ctrl-d.
+func (s *Shell) editLine() (string, error) {
+	var line []rune
+	hist := len(s.History)
+
+	redraw := func() {
+		fmt.Fprint(s.Out, "\r\x1b[K"+s.Prompt+string(line))
+	}
+
+	for {
+		r, _, err := s.rd.ReadRune()
+		if err != nil {
+			return string(line), err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(s.Out, "\r\n")
+			return string(line), nil
+
+		case 3: // ctrl-c drops the line
+			line = line[:0]
+			fmt.Fprint(s.Out, "^C\r\n")
+			redraw()
+
+		case 4: // ctrl-d ends the session on an empty line
+			if len(line) == 0 {
+				fmt.Fprint(s.Out, "\r\n")
+				return "", io.EOF
+			}
+
+		case 127, 8:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				redraw()
+			}
+
+		case '\t':
+			line = s.completeLine(line)
+			redraw()
+
+		case 27: // escape sequences, only the arrows are used
+			b1, _, _ := s.rd.ReadRune()
+			b2, _, _ := s.rd.ReadRune()
+			if b1 != '[' {
+				continue
+			}
+			switch {
+			case b2 == 'A' && hist > 0:
+				hist--
+				line = []rune(s.History[hist])
+			case b2 == 'B' && hist < len(s.History)-1:
+				hist++
+				line = []rune(s.History[hist])
+			case b2 == 'B':
+				hist = len(s.History)
+				line = line[:0]
+			}
+			redraw()
+
+		default:
+			if r >= ' ' {
+				line = append(line, r)
+				fmt.Fprint(s.Out, string(r))
+			}
+		}
+	}
+}
+
+// completeLine finishes the last word when there is one candidate,
+// extends it to the longest common prefix otherwise and lists the
+// candidates when that does not add anything.
+func (s *Shell) completeLine(line []rune) []rune {
+	str := string(line)
+	found := s.Complete(str)
+	if len(found) == 0 {
+		return line
+	}
+
+	start := strings.LastIndexAny(str, " \t") + 1
+	word := str[start:]
+	common := found[0]
+	for _, f := range found[1:] {
+		for !strings.HasPrefix(f, common) {
+			common = common[:len(common)-1]
+		}
+	}
+
+	if len(found) == 1 {
+		return []rune(str[:start] + common + " ")
+	}
+	if len(common) > len(word) {
+		return []rune(str[:start] + common)
+	}
+	fmt.Fprint(s.Out, "\r\n"+strings.Join(found, "  ")+"\r\n")
+	return line
+}
+
+func (s *Shell) loadHistory() {
+	if s.HistoryFile == "" {
+		return
+	}
+	b, err := os.ReadFile(s.HistoryFile)
+	if err != nil {
+		return
+	}
+	for _, l := range strings.Split(string(b), "\n") {
+		if l != "" {
+			s.History = append(s.History, l)
+		}
+	}
+}
+
+func (s *Shell) saveHistory() {
+	if s.HistoryFile == "" {
+		return
+	}
+	_ = os.WriteFile(s.HistoryFile, []byte(strings.Join(s.History, "\n")+"\n"), 0600)
+}
+
+func setRaw(on bool) {
+	arg := []string{"-raw", "echo"}
+	if on {
+		arg = []string{"raw", "-echo"}
+	}
+	cmd := exec.Command("stty", arg...)
+	cmd.Stdin = os.Stdin
+	_ = cmd.Run()
+}
@@ -0,0 +1,124 @@
+package boa
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func shellItems() map[string]CmdLineItem {
+	return map[string]CmdLineItem{
+		"deploy":    {Id: 1, Name: "deploy", ParamType: TypeString, ParamCount: 1, ShortHelp: "deploy: ship it", ChNames: []string{"--now", "--secret", "--gone"}},
+		"--now":     {Id: 2, Name: "--now", IsFlag: true, ParName: "deploy", ShortHelp: "--now: do not wait"},
+		"--secret":  {Id: 3, Name: "--secret", IsFlag: true, ParName: "deploy", IsHidden: true},
+		"--gone":    {Id: 4, Name: "--gone", IsFlag: true, ParName: "deploy", IsDeleted: true},
+		"--verbose": {Id: 5, Name: "--verbose", Alias: "-v", IsFlag: true, ShortHelp: "--verbose: say more"},
+		"status":    {Id: 6, Name: "status", ShortHelp: "status: show the state"},
+	}
+}
+
+func TestShellLoop(t *testing.T) {
+	var out bytes.Buffer
+	var ran []string
+	s := &Shell{
+		Items:  shellItems(),
+		Prompt: "> ",
+		In:     strings.NewReader("deploy 'my app' --now\n\nhistory\nhelp\nhelp deploy\ndeploy\nstatus\nstatus --count x\nexit\nstatus\n"),
+		Out:    &out,
+		Run: func(cli *CLI) error {
+			if _, ok := cli.Items["deploy"]; ok {
+				v, _ := cli.String("deploy")
+				ran = append(ran, "deploy "+v)
+				return nil
+			}
+			ran = append(ran, "status")
+			return errors.New("status failed")
+		},
+	}
+	s.Items["--count"] = CmdLineItem{Id: 7, Name: "--count", IsFlag: true, ParamType: TypeInt, ParamCount: 1}
+	if err := s.Loop(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the line after exit is not run
+	if want := []string{"deploy my app", "status"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %q, want %q", ran, want)
+	}
+	if want := []string{"deploy 'my app' --now", "history", "help", "help deploy", "deploy", "status", "status --count x", "exit"}; !reflect.DeepEqual(s.History, want) {
+		t.Errorf("history = %q, want %q", s.History, want)
+	}
+	for _, want := range []string{
+		"   1  deploy 'my app' --now\n   2  history\n",
+		"--verbose | -v    say more\ndeploy          ship it\nstatus          show the state\n",
+		"NoRequiredString",
+		"status failed",
+		"NotAnInt",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestShellComplete(t *testing.T) {
+	s := &Shell{Items: shellItems()}
+	for _, tc := range []struct {
+		line string
+		want []string
+	}{
+		{"", []string{"--now", "--verbose", "-v", "deploy", "exit", "help", "history", "status"}},
+		{"de", []string{"deploy"}},
+		{"deploy ", []string{"--now", "--verbose", "-v"}},
+		{"deploy --s", nil},
+		{"deploy --g", nil},
+		{"status --v", []string{"--verbose"}},
+	} {
+		if got := s.Complete(tc.line); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Complete(%q) = %q, want %q", tc.line, got, tc.want)
+		}
+	}
+}
+
+func TestShellEditLine(t *testing.T) {
+	var out bytes.Buffer
+	s := &Shell{Items: shellItems(), Out: &out, Raw: true, History: []string{"status", "deploy x"}}
+
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"dep\tprod\r", "deploy prod"},
+		{"statux\x7fs\r", "status"},
+		{"\x1b[A\x1b[A\r", "status"},
+		{"\x1b[A\x1b[B\x1b[B\r", ""},
+		{"junk\x03ok\r", "ok"},
+	} {
+		s.rd = bufio.NewReader(strings.NewReader(tc.in))
+		got, err := s.editLine()
+		if err != nil || got != tc.want {
+			t.Errorf("editLine(%q) = %q, %v, want %q", tc.in, got, err, tc.want)
+		}
+	}
+}
+
+func TestShellHistoryFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(file, []byte("status\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s := &Shell{Items: shellItems(), In: strings.NewReader("deploy x\n"), Out: &bytes.Buffer{}, HistoryFile: file}
+	if err := s.Loop(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "status\ndeploy x\n" {
+		t.Errorf("history file = %q", b)
+	}
+}
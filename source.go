@@ -0,0 +1,31 @@
+package boa
+
+// Source tells where the value of a parsed item came from.
+type Source int
+
+const (
+	SourceNone    Source = iota // the item was not found
+	SourceArgs                  // the value was on the command line
+	SourceDefault               // the item was given without a value and DefaultValue was used
+	SourcePrompt                // the value was asked for by a Prompter
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceArgs:
+		return "args"
+	case SourceDefault:
+		return "default"
+	case SourcePrompt:
+		return "prompt"
+	}
+	return "none"
+}
+
+// Source returns where the value of item came from.
+func (C *CLI) Source(item string) Source {
+	if it, ok := C.Items[item]; ok {
+		return it.Source
+	}
+	return SourceNone
+}
@@ -26,6 +26,10 @@ func TypeToString(p ParameterType) string {
 		return "IPv4Address"
 	case TypePhone, TypePhoneSlice:
 		return "Phone Number"
+	case TypeStringMap:
+		return "String Map"
+	case TypeIntMap:
+		return "Integer Map"
 	}
 	return "Bool"
 }
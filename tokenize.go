@@ -0,0 +1,143 @@
+package boa
+
+import "strings"
+
+// token is a word from a line along with the byte offset it starts at.
+type token struct {
+	text string
+	pos  int
+}
+
+// SplitArgs breaks a line into arguments the way a POSIX shell would.
+// Words are separated by blanks, single quotes keep everything between
+// them literally, double quotes allow \" \\ \$ and \` escapes, and a
+// backslash outside of quotes escapes the character that follows it.
+func SplitArgs(line string) ([]string, error) {
+	toks, _, err := tokenize(line, false, nil)
+	var args []string
+	for _, t := range toks {
+		args = append(args, t.text)
+	}
+	return args, err
+}
+
+// tokenize does the work for SplitArgs. When comments is set a '#' at the
+// start of a word skips the rest of its line. When expand is not nil $NAME
+// and ${NAME} outside of single quotes are replaced by expand(NAME); the
+// result is never split into more words. On error the offset of the quote
+// that was never closed is returned along with it.
+func tokenize(line string, comments bool, expand func(string) string) ([]token, int, error) {
+	var toks []token
+	var word strings.Builder
+	start := -1 // offset of the word being built, -1 between words
+	var quote byte
+	qpos := 0
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+				continue
+			}
+			word.WriteByte(c)
+
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+				continue
+			}
+			if c == '\\' && i+1 < len(line) && strings.IndexByte("\"\\$`", line[i+1]) >= 0 {
+				i++
+				c = line[i]
+			} else if c == '$' && expand != nil {
+				if v, next, ok := expandVar(line, i, expand); ok {
+					word.WriteString(v)
+					i = next
+					continue
+				}
+			}
+			word.WriteByte(c)
+
+		case c == '\'' || c == '"':
+			quote = c
+			qpos = i
+			if start < 0 {
+				start = i
+			}
+
+		case c == '\\':
+			if start < 0 {
+				start = i
+			}
+			if i+1 < len(line) {
+				i++
+				if line[i] != '\n' { // escaped newline continues the line
+					word.WriteByte(line[i])
+				} else if word.Len() == 0 {
+					start = -1
+				}
+			}
+
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if start >= 0 {
+				toks = append(toks, token{word.String(), start})
+				word.Reset()
+				start = -1
+			}
+
+		case c == '#' && comments && start < 0:
+			for i < len(line) && line[i] != '\n' {
+				i++
+			}
+
+		default:
+			if start < 0 {
+				start = i
+			}
+			if c == '$' && expand != nil {
+				if v, next, ok := expandVar(line, i, expand); ok {
+					word.WriteString(v)
+					i = next
+					continue
+				}
+			}
+			word.WriteByte(c)
+		}
+	}
+
+	if quote != 0 {
+		return toks, qpos, Errorf(BeUnterminatedQuote, string(quote), line)
+	}
+	if start >= 0 {
+		toks = append(toks, token{word.String(), start})
+	}
+	return toks, 0, nil
+}
+
+// expandVar expands the variable reference starting at the '$' at
+// line[i]. It returns the value and the offset of the last byte of the
+// reference, or false when the '$' does not start a reference.
+func expandVar(line string, i int, expand func(string) string) (string, int, bool) {
+	isName := func(c byte, first bool) bool {
+		return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+	}
+
+	if i+1 < len(line) && line[i+1] == '{' {
+		end := strings.IndexByte(line[i+2:], '}')
+		if end <= 0 {
+			return "", i, false
+		}
+		return expand(line[i+2 : i+2+end]), i + 2 + end, true
+	}
+
+	j := i + 1
+	for j < len(line) && isName(line[j], j == i+1) {
+		j++
+	}
+	if j == i+1 {
+		return "", i, false
+	}
+	return expand(line[i+1 : j]), j - 1, true
+}
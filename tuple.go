@@ -0,0 +1,72 @@
+package boa
+
+import "strconv"
+
+// ParamSpec names and types one of the values of an item that takes a
+// fixed number of values, as in --range start end. Type may be left at
+// TypeBool, its zero value, to use the ParamType of the item.
+type ParamSpec struct {
+	Name string
+	Type ParameterType
+}
+
+func (c CmdLineItem) paramName(pos int) string {
+	if pos < len(c.Params) && c.Params[pos].Name != "" {
+		return c.Params[pos].Name
+	}
+	return strconv.Itoa(pos + 1)
+}
+
+func (c CmdLineItem) paramType(pos int) ParameterType {
+	if pos < len(c.Params) && c.Params[pos].Type != TypeBool {
+		return c.Params[pos].Type
+	}
+	return c.ParamType
+}
+
+// parseTuple takes exactly ParamCount values for an item that is not a
+// slice. Each value is converted to the type of its position and the
+// values are kept in order in a []interface{}.
+func parseTuple(args []string, cmd *CmdLineItem, isItem func(string) bool) (int, *CmdLineItem, error) {
+	vals := make([]interface{}, 0, cmd.ParamCount)
+	j := 1
+	for pos := 0; pos < cmd.ParamCount; pos++ {
+		if j >= len(args) || args[j] == "--" || isItem(args[j]) {
+			if j < len(args) && args[j] == "--" {
+				j++
+			}
+			return j, cmd, Errorf(BeMissingParam, cmd.Name, pos+1, cmd.paramName(pos))
+		}
+		v, err := convertParam(*cmd, cmd.paramType(pos), args[j])
+		if err != nil {
+			return j + 1, cmd, err
+		}
+		vals = append(vals, v)
+		j++
+	}
+	if j < len(args) && args[j] == "--" {
+		j++
+	}
+
+	cmd.Value = vals
+	return j, cmd, nil
+}
+
+// convertParam converts a single value to type t by running it through
+// getCmdValues, so it is checked exactly like any other value of that type.
+func convertParam(it CmdLineItem, t ParameterType, v string) (interface{}, error) {
+	it.ParamType = t
+	it.ParamCount = 1
+	it.ChNames = nil
+	it.DefaultValue = ""
+	it.Params = nil
+	ix, _ := newIndex(map[string]CmdLineItem{it.Name: it}, false)
+	_, cm, err := getCmdValues(ix, it.Name, []string{it.Name, v})
+	if err != nil {
+		return nil, err
+	}
+	if cm == nil {
+		return nil, Errorf(BeUnsupportedType)
+	}
+	return cm.Value, nil
+}
@@ -0,0 +1,90 @@
+package boa
+
+// UnknownPolicy says what happens to arguments that name no item.
+type UnknownPolicy int
+
+const (
+	// UnknownInherit uses the policy of the parent command, or the one
+	// given to WithUnknown at the top level.
+	UnknownInherit UnknownPolicy = iota
+	// UnknownError reports every unknown argument as BeInvalidCommand.
+	UnknownError
+	// UnknownCollect gathers unknown arguments in Unknown and goes on.
+	UnknownCollect
+	// UnknownStop ends parsing at the first unknown argument. It and
+	// everything after it are put in Unknown as they were given, which
+	// suits wrappers such as 'app run IMAGE args...'.
+	UnknownStop
+)
+
+// WithUnknown sets the policy for unknown arguments of commands that do
+// not set one themselves through CmdLineItem.OnUnknown.
+func WithUnknown(p UnknownPolicy) Option {
+	return func(C *CLI) {
+		C.conf.onUnknown = p
+	}
+}
+
+// WithPassthrough ends parsing at a '--' that is not taken by an item
+// as the end of its list. Everything after it is kept in Passthrough
+// exactly as it was given.
+func WithPassthrough() Option {
+	return func(C *CLI) {
+		C.conf.passthrough = true
+	}
+}
+
+// Unknown returns the arguments kept by the UnknownCollect and
+// UnknownStop policies.
+func (C *CLI) Unknown() []string {
+	return C.unknown
+}
+
+// Passthrough returns the arguments that came after '--' when
+// WithPassthrough is in effect.
+func (C *CLI) Passthrough() []string {
+	return C.passed
+}
+
+func (ix *itemIndex) isKnown(a string) bool {
+	if _, ok := ix.lookup(a); ok {
+		return true
+	}
+	_, ok := ix.lookup("--" + a)
+	return ok
+}
+
+// unknownPolicy finds the policy in effect below the command level,
+// walking up its parents until one sets a policy.
+func unknownPolicy(cmds map[string]CmdLineItem, level *CmdLineItem, top UnknownPolicy) UnknownPolicy {
+	seen := make(map[string]bool)
+	for level != nil && !seen[level.Name] {
+		if level.OnUnknown != UnknownInherit {
+			return level.OnUnknown
+		}
+		seen[level.Name] = true
+		parent, ok := cmds[level.ParName]
+		if !ok {
+			break
+		}
+		level = &parent
+	}
+	if top == UnknownInherit {
+		return UnknownError
+	}
+	return top
+}
+
+// WithInterspersed decides whether flags may follow the first positional
+// argument, the value of a command or an argument that is not an item.
+// It is allowed unless this is given false; then parsing ends after the
+// value of a command and everything after it is put in Unknown untouched,
+// so in 'deploy x --verbose' --verbose is not parsed. An argument that is
+// not an item ends parsing as well; under UnknownCollect or UnknownStop
+// it and everything after it are put in Unknown, under UnknownError it
+// is reported and the rest is ignored.
+func WithInterspersed(allow bool) Option {
+	return func(C *CLI) {
+		C.conf.noIntersperse = !allow
+	}
+}
@@ -0,0 +1,92 @@
+package boa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNotInterspersed(t *testing.T) {
+	items := map[string]CmdLineItem{
+		"deploy":    {Id: 1, Name: "deploy", ParamType: TypeString, ParamCount: 1},
+		"--verbose": {Id: 2, Name: "--verbose", IsFlag: true},
+	}
+	for _, tc := range []struct {
+		args    []string
+		verbose bool
+		unknown []string
+	}{
+		{[]string{"deploy", "x", "--verbose"}, false, []string{"--verbose"}},
+		{[]string{"--verbose", "deploy", "x", "y"}, true, []string{"y"}},
+		{[]string{"--verbose", "deploy", "x"}, true, nil},
+	} {
+		cli := NewParser(items, WithInterspersed(false)).Parse(tc.args)
+		if cli.HasErrors() {
+			t.Errorf("%q: errors: %s", tc.args, cli.Errors())
+		}
+		if v, _ := cli.String("deploy"); v != "x" {
+			t.Errorf("%q: deploy = %q, want x", tc.args, v)
+		}
+		if _, ok := cli.Items["--verbose"]; ok != tc.verbose {
+			t.Errorf("%q: --verbose parsed = %t, want %t", tc.args, ok, tc.verbose)
+		}
+		if !reflect.DeepEqual(cli.Unknown(), tc.unknown) {
+			t.Errorf("%q: unknown = %q, want %q", tc.args, cli.Unknown(), tc.unknown)
+		}
+	}
+
+	cli := NewParser(items).Parse([]string{"deploy", "x", "--verbose"})
+	if _, ok := cli.Items["--verbose"]; !ok {
+		t.Errorf("interspersed by default: --verbose was not parsed")
+	}
+}
+
+func TestUnknownPolicies(t *testing.T) {
+	items := map[string]CmdLineItem{
+		"run":       {Id: 1, Name: "run", ParamType: TypeString, ParamCount: 1, OnUnknown: UnknownStop},
+		"build":     {Id: 2, Name: "build", OnUnknown: UnknownError},
+		"--verbose": {Id: 3, Name: "--verbose", IsFlag: true},
+		"--tags":    {Id: 4, Name: "--tags", IsFlag: true, ParamType: TypeStringSlice, ParamCount: ZeroOrMore},
+	}
+	for _, tc := range []struct {
+		opts    []Option
+		args    []string
+		verbose bool
+		unknown []string
+		passed  []string
+		code    ParseErrCode // BeExternalError for none
+	}{
+		{nil, []string{"--foo", "--verbose"}, true, nil, nil, BeInvalidCommand},
+		{[]Option{WithUnknown(UnknownError)}, []string{"--foo"}, false, nil, nil, BeInvalidCommand},
+		{[]Option{WithUnknown(UnknownCollect)}, []string{"--foo", "--verbose", "bar"}, true, []string{"--foo", "bar"}, nil, BeExternalError},
+		{[]Option{WithUnknown(UnknownStop)}, []string{"--verbose", "--foo", "bar", "--verbose"}, true, []string{"--foo", "bar", "--verbose"}, nil, BeExternalError},
+
+		// a command's own policy wins over the top level one
+		{nil, []string{"run", "img", "--foo", "bar"}, false, []string{"--foo", "bar"}, nil, BeExternalError},
+		{nil, []string{"--verbose", "run", "img", "--foo", "--verbose"}, true, []string{"--foo", "--verbose"}, nil, BeExternalError},
+		{[]Option{WithUnknown(UnknownCollect)}, []string{"build", "--foo"}, false, nil, nil, BeInvalidCommand},
+		{[]Option{WithUnknown(UnknownCollect)}, []string{"--foo", "build", "--verbose"}, true, []string{"--foo"}, nil, BeExternalError},
+
+		// '--' ends parsing unless an item takes it as the end of its list
+		{[]Option{WithPassthrough()}, []string{"--verbose", "--", "--foo", "run"}, true, nil, []string{"--foo", "run"}, BeExternalError},
+		{[]Option{WithPassthrough()}, []string{"--tags", "a", "--", "--verbose"}, true, nil, nil, BeExternalError},
+		{[]Option{WithPassthrough()}, []string{"--tags", "a", "--", "--", "x"}, false, nil, []string{"x"}, BeExternalError},
+		{[]Option{WithPassthrough(), WithUnknown(UnknownCollect)}, []string{"--foo", "--", "--bar"}, false, []string{"--foo"}, []string{"--bar"}, BeExternalError},
+	} {
+		cli := NewParser(items, tc.opts...).Parse(tc.args)
+		if tc.code == BeExternalError && cli.HasErrors() {
+			t.Errorf("%q: errors: %s", tc.args, cli.Errors())
+		}
+		if tc.code != BeExternalError && !hasCode(cli, tc.code) {
+			t.Errorf("%q: errors %q, want %s", tc.args, cli.Errors(), tc.code)
+		}
+		if _, ok := cli.Items["--verbose"]; ok != tc.verbose {
+			t.Errorf("%q: --verbose parsed = %t, want %t", tc.args, ok, tc.verbose)
+		}
+		if !reflect.DeepEqual(cli.Unknown(), tc.unknown) {
+			t.Errorf("%q: unknown = %q, want %q", tc.args, cli.Unknown(), tc.unknown)
+		}
+		if !reflect.DeepEqual(cli.Passthrough(), tc.passed) {
+			t.Errorf("%q: passthrough = %q, want %q", tc.args, cli.Passthrough(), tc.passed)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package boa
+
+import (
+	"sort"
+	"strings"
+)
+
+// Usage returns the help of every item, in the order the items were
+// defined, under a line naming the application and its description and
+// followed by the examples and epilog of the AppInfo.
+func (C *CLI) Usage() string {
+	var b strings.Builder
+	if C.Application != "" {
+		heading := "Usage of " + C.Application + ":"
+		if th := C.conf.styles(); th != nil {
+			heading = th.Heading.paint(heading)
+		}
+		b.WriteString(heading + "\n")
+	}
+	b.WriteString(C.usageHead())
+	for _, name := range C.helpOrder() {
+		h := strings.TrimRight(C.itemHelp(name), "\n")
+		b.WriteString(h + "\n")
+	}
+	b.WriteString(C.usageTail())
+	return b.String()
+}
+
+// helpOrder lists the names in AllHelp by item Id, or by name when the
+// item definitions are not known.
+func (C *CLI) helpOrder() []string {
+	names := make([]string, 0, len(C.AllHelp))
+	for name := range C.AllHelp {
+		names = append(names, name)
+	}
+	id := func(name string) int {
+		if C.index != nil {
+			if it, ok := C.index.cmds[name]; ok {
+				return it.Id
+			}
+		}
+		return 0
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if id(names[i]) != id(names[j]) {
+			return id(names[i]) < id(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -1,13 +1,17 @@
 package boa
 
+import "sort"
+
 // validateRequirements ts called after all the commands
 // and flags have been parsed.
 func validateRequirements(cmds map[string]CmdLineItem, cli *CLI) {
+	var missing []CmdLineItem
+check:
 	for _, it := range cmds {
-		if it.IsRequired {
+		if it.IsRequired && !it.IsDeleted && (!it.IsExperimental || cli.experimental) {
 			_, found := cli.Items[it.Name]
 			if !found {
-				cli.SetError(Errorf(BeNoRequiredItem, it.Name))
+				missing = append(missing, it)
 			}
 		}
 		if it.IsExclusive {
@@ -18,7 +22,7 @@ func validateRequirements(cmds map[string]CmdLineItem, cli *CLI) {
 
 				if it.IsFlag && i.IsFlag {
 					cli.SetError(Errorf(BeNoExclusiveItem, it.Name, i.Name))
-					return
+					break check
 				}
 
 				if !i.IsFlag && i.IsFlag {
@@ -28,4 +32,21 @@ func validateRequirements(cmds map[string]CmdLineItem, cli *CLI) {
 			}
 		}
 	}
+
+	// prompt in the order the items were defined
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Id < missing[j].Id })
+	for _, it := range missing {
+		if cli.conf.prompter == nil || !cli.conf.prompter.Interactive {
+			cli.SetError(Errorf(BeNoRequiredItem, it.Name))
+			continue
+		}
+		it.ShortHelp, it.LongHelp = cli.conf.helpOf(it) // ask in the user's language
+		cm, err := cli.conf.prompter.prompt(cmds, it, cli.conf.styles())
+		if err != nil {
+			cli.SetError(err)
+			continue
+		}
+		cm.Source = SourcePrompt
+		cli.Items[cm.Name] = *cm
+	}
 }